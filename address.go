@@ -0,0 +1,114 @@
+package rawdecodebtc
+
+import (
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// Address types reported in Vout.AddressType.
+const (
+	AddressP2PK           = "P2PK"
+	AddressP2PKH          = "P2PKH"
+	AddressP2SH           = "P2SH"
+	AddressP2MS           = "P2MS"
+	AddressP2WPKH         = "P2WPKH"
+	AddressP2WSH          = "P2WSH"
+	AddressP2TR           = "P2TR"
+	AddressWitnessUnknown = "witness-unknown"
+	AddressNullData       = "OP_RETURN"
+	AddressNonstandard    = "nonstandard"
+)
+
+// taprootWitnessVersion and taprootProgramLen identify a P2TR (BIP341)
+// witness program.
+const (
+	taprootWitnessVersion = 1
+	taprootProgramLen     = 32
+)
+
+// addressType returns the conventional label of an output of class
+// scriptClass with script pkScript. Segwit v1 and later programs, which the
+// btcd version in use classifies as nonstandard, are recognized from
+// pkScript.
+func addressType(scriptClass txscript.ScriptClass, pkScript []byte) string {
+	switch scriptClass {
+	case txscript.PubKeyTy:
+		return AddressP2PK
+	case txscript.PubKeyHashTy:
+		return AddressP2PKH
+	case txscript.ScriptHashTy:
+		return AddressP2SH
+	case txscript.MultiSigTy:
+		return AddressP2MS
+	case txscript.WitnessV0PubKeyHashTy:
+		return AddressP2WPKH
+	case txscript.WitnessV0ScriptHashTy:
+		return AddressP2WSH
+	case txscript.NullDataTy:
+		return AddressNullData
+	}
+
+	version, program, err := txscript.ExtractWitnessProgramInfo(pkScript)
+	switch {
+	case err != nil:
+		return AddressNonstandard
+	case version == taprootWitnessVersion && len(program) == taprootProgramLen:
+		return AddressP2TR
+	default:
+		return AddressWitnessUnknown
+	}
+}
+
+// altAddresses returns the addresses, other than the one reported, of the key
+// or script paid by an output of class scriptClass with script pkScript, as
+// listed by Vout.AltAddresses. They are nil for other classes.
+func altAddresses(scriptClass txscript.ScriptClass, pkScript []byte, chainParams *chaincfg.Params) []btcutil.Address {
+	var keyHash []byte
+	switch scriptClass {
+	case txscript.PubKeyHashTy:
+		// OP_DUP OP_HASH160 <20 byte hash> OP_EQUALVERIFY OP_CHECKSIG
+		keyHash = pkScript[3:23]
+	case txscript.WitnessV0PubKeyHashTy:
+		// OP_0 <20 byte hash>
+		keyHash = pkScript[2:22]
+	case txscript.PubKeyTy:
+		// Only compressed keys, <33 byte key> OP_CHECKSIG, can be
+		// spent through segwit.
+		if pkScript[0] != txscript.OP_DATA_33 {
+			return nil
+		}
+		keyHash = btcutil.Hash160(pkScript[1:34])
+	case txscript.WitnessV0ScriptHashTy:
+		nested, err := btcutil.NewAddressScriptHash(pkScript, chainParams)
+		if err != nil {
+			return nil
+		}
+		return []btcutil.Address{nested}
+	default:
+		return nil
+	}
+
+	p2pkh, err := btcutil.NewAddressPubKeyHash(keyHash, chainParams)
+	if err != nil {
+		return nil
+	}
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(keyHash, chainParams)
+	if err != nil {
+		return nil
+	}
+	program, err := txscript.PayToAddrScript(p2wpkh)
+	if err != nil {
+		return nil
+	}
+	nested, err := btcutil.NewAddressScriptHash(program, chainParams)
+	if err != nil {
+		return nil
+	}
+
+	// The address of a P2PK output is reported in its P2PKH form.
+	if scriptClass == txscript.WitnessV0PubKeyHashTy {
+		return []btcutil.Address{p2pkh, nested}
+	}
+	return []btcutil.Address{p2wpkh, nested}
+}
@@ -0,0 +1,141 @@
+package rawdecodebtc
+
+import (
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Anomaly codes reported in TxRawDecodeResult.Anomalies.
+const (
+	// AnomalyDuplicateInput flags a transaction spending the same
+	// outpoint more than once.
+	AnomalyDuplicateInput = "duplicate-input"
+
+	// AnomalyZeroValueOutput flags an output carrying no value that is
+	// not an OP_RETURN data output.
+	AnomalyZeroValueOutput = "zero-value-output"
+
+	// AnomalyUnparseableScript flags an output whose script fails to
+	// parse.
+	AnomalyUnparseableScript = "unparseable-script"
+
+	// AnomalyNullPrevout flags a non-coinbase input spending the all-zero
+	// outpoint hash reserved for coinbases.
+	AnomalyNullPrevout = "null-prevout"
+
+	// AnomalySighashSingleBug flags a legacy input signed with
+	// SIGHASH_SINGLE without a matching output, which makes the signature
+	// commit to the value 1 instead of the transaction. Segwit signatures
+	// are not affected, as BIP143 and BIP341 fixed the quirk.
+	AnomalySighashSingleBug = "sighash-single-bug"
+
+	// AnomalyValueOutOfRange flags an output value, or a total of output
+	// values, outside the valid money range.
+	AnomalyValueOutOfRange = "value-out-of-range"
+
+	// AnomalyNoInputs flags a transaction without inputs, such as a
+	// template yet to be funded.
+	AnomalyNoInputs = "no-inputs"
+
+	// AnomalyOversizedOpReturn flags an OP_RETURN output carrying more
+	// data than allowed by WithTrimMemo, DefaultMaxDataCarrierSize by
+	// default.
+	AnomalyOversizedOpReturn = "oversized-op-return"
+)
+
+// sigHashMask extracts the base signature hash type, ignoring the
+// ANYONECANPAY flag and any undefined bits, as consensus does.
+const sigHashMask = 0x1f
+
+// detectAnomalies returns the anomaly codes that apply to mtx decoded with
+// cfg, each listed once, in the order the checks above are declared.
+func detectAnomalies(mtx *wire.MsgTx, cfg *decodeConfig) []string {
+	var anomalies []string
+	add := func(code string, found bool) {
+		if found {
+			anomalies = append(anomalies, code)
+		}
+	}
+
+	coinbase := blockchain.IsCoinBaseTx(mtx)
+	add(AnomalyDuplicateInput, hasDuplicateInputs(mtx))
+
+	var zeroValue, unparseable bool
+	for _, txOut := range mtx.TxOut {
+		if txOut.Value == 0 && !txscript.IsUnspendable(txOut.PkScript) {
+			zeroValue = true
+		}
+		if _, err := txscript.DisasmString(txOut.PkScript); err != nil {
+			unparseable = true
+		}
+	}
+	add(AnomalyZeroValueOutput, zeroValue)
+	add(AnomalyUnparseableScript, unparseable)
+
+	var nullPrevout, sighashSingleBug bool
+	for i, txIn := range mtx.TxIn {
+		if coinbase {
+			break
+		}
+		if txIn.PreviousOutPoint.Hash == (chainhash.Hash{}) {
+			nullPrevout = true
+		}
+		if i >= len(mtx.TxOut) && signsSighashSingle(txIn) {
+			sighashSingleBug = true
+		}
+	}
+	add(AnomalyNullPrevout, nullPrevout)
+	add(AnomalySighashSingleBug, sighashSingleBug)
+	add(AnomalyValueOutOfRange, checkMoneyRange(mtx) != nil)
+	add(AnomalyNoInputs, len(mtx.TxIn) == 0)
+
+	var oversized bool
+	for _, txOut := range mtx.TxOut {
+		if size, ok := DataCarrierSize(txOut.PkScript); ok &&
+			size > cfg.dataCarrierLimit() {
+			oversized = true
+		}
+	}
+	add(AnomalyOversizedOpReturn, oversized)
+
+	return anomalies
+}
+
+// hasDuplicateInputs reports whether two inputs of mtx spend the same
+// outpoint.
+func hasDuplicateInputs(mtx *wire.MsgTx) bool {
+	seen := make(map[wire.OutPoint]struct{}, len(mtx.TxIn))
+	for _, txIn := range mtx.TxIn {
+		if _, exists := seen[txIn.PreviousOutPoint]; exists {
+			return true
+		}
+		seen[txIn.PreviousOutPoint] = struct{}{}
+	}
+	return false
+}
+
+// signsSighashSingle reports whether any signature found in the scriptSig of
+// the legacy input txIn uses SIGHASH_SINGLE. Inputs with a witness are
+// signed as per BIP143 or BIP341 and never report it.
+func signsSighashSingle(txIn *wire.TxIn) bool {
+	if len(txIn.Witness) > 0 {
+		return false
+	}
+	pushes, err := txscript.PushedData(txIn.SignatureScript)
+	if err != nil {
+		return false
+	}
+
+	for _, e := range pushes {
+		if !isSignatureLike(e) {
+			continue
+		}
+		hashType := txscript.SigHashType(e[len(e)-1])
+		if hashType&sigHashMask == txscript.SigHashSingle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package rawdecodebtc
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestSighashSingleBug checks that SIGHASH_SINGLE signatures without a
+// matching output are only flagged for legacy inputs.
+func TestSighashSingleBug(t *testing.T) {
+	// DER framing around six placeholder bytes, then SIGHASH_SINGLE.
+	sig := []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01, 0x03}
+	pubKey := make([]byte, 33)
+	pubKey[0] = 0x02
+
+	tests := []struct {
+		name      string
+		sigScript []byte
+		witness   wire.TxWitness
+		want      bool
+	}{
+		{
+			name:      "legacy",
+			sigScript: append([]byte{byte(len(sig))}, sig...),
+			want:      true,
+		},
+		{
+			name:    "P2WPKH",
+			witness: wire.TxWitness{sig, pubKey},
+			want:    false,
+		},
+	}
+
+	for _, test := range tests {
+		mtx := wire.NewMsgTx(wire.TxVersion)
+		mtx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0),
+			nil, nil))
+		mtx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{2}, 0),
+			test.sigScript, test.witness))
+		mtx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+
+		txReply, err := FromWire(mtx, "mainnet")
+		if err != nil {
+			t.Fatalf("%s: FromWire: %v", test.name, err)
+		}
+		if got := hasAnomaly(txReply, AnomalySighashSingleBug); got != test.want {
+			t.Errorf("%s: got %s %v, want %v", test.name,
+				AnomalySighashSingleBug, got, test.want)
+		}
+	}
+}
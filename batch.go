@@ -0,0 +1,41 @@
+package rawdecodebtc
+
+import (
+	"runtime"
+	"sync"
+)
+
+// FromHexBatch decodes every hex encoded transaction of messages. The
+// returned results and errors are index aligned with messages, so a failing
+// entry leaves a zero result and a non-nil error at its position without
+// aborting the rest of the batch. Decoding is spread over a worker pool
+// bounded by the number of CPUs.
+func FromHexBatch(messages []string, net string) ([]TxRawDecodeResult, []error) {
+	results := make([]TxRawDecodeResult, len(messages))
+	errs := make([]error, len(messages))
+
+	workers := runtime.NumCPU()
+	if workers > len(messages) {
+		workers = len(messages)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = FromHex(messages[i], net)
+			}
+		}()
+	}
+
+	for i := range messages {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,161 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// BlockHeaderResult models the header fields of a decoded block.
+type BlockHeaderResult struct {
+	Hash       string `json:"hash"`
+	Version    int32  `json:"version"`
+	PrevBlock  string `json:"previousblockhash"`
+	MerkleRoot string `json:"merkleroot"`
+	Time       int64  `json:"time"`
+	Bits       string `json:"bits"`
+	Nonce      uint32 `json:"nonce"`
+}
+
+// BlockFromHex decodes a raw block from Hex payload and returns its header
+// along with every transaction decoded as by FromHex.
+func BlockFromHex(blockHex string, net string) (header BlockHeaderResult, txs []TxRawDecodeResult, err error) {
+	cparam, err := paramsForNet(net)
+	if err != nil {
+		return
+	}
+
+	rawBlock, err := HexDecodeRawTxString(blockHex)
+	if err != nil {
+		return
+	}
+
+	return decodeBlock(rawBlock, cparam)
+}
+
+// decodeBlock deserializes the raw block rawBlock and decodes its header and
+// transactions.
+func decodeBlock(rawBlock []byte, cparam *chaincfg.Params) (header BlockHeaderResult, txs []TxRawDecodeResult, err error) {
+	cr := &countingReader{r: bytes.NewReader(rawBlock)}
+	var msgBlock wire.MsgBlock
+	err = msgBlock.Deserialize(cr)
+	if err != nil {
+		err = &DecodeError{Stage: "block", Offset: cr.n, Err: err}
+		return
+	}
+
+	header = newBlockHeaderResult(&msgBlock.Header)
+	txs = make([]TxRawDecodeResult, len(msgBlock.Transactions))
+	for i, mtx := range msgBlock.Transactions {
+		txs[i] = newTxRawDecodeResult(mtx, cparam, nil)
+	}
+	return
+}
+
+// EncodeBlockJSON decodes the transactions of a raw block from Hex payload
+// and writes them to w as a JSON array, each encoded as by json.Marshal. The
+// transactions are decoded and written one at a time rather than collected
+// first as by BlockFromHex, which keeps memory bounded for large blocks. As
+// the array is written while decoding, w holds a truncated array when an
+// error is returned after the first transaction.
+func EncodeBlockJSON(w io.Writer, blockHex string, net string) error {
+	cfg, err := newDecodeConfig([]Option{WithNetwork(net)})
+	if err != nil {
+		return err
+	}
+
+	rawBlock, err := HexDecodeRawTxString(blockHex)
+	if err != nil {
+		return err
+	}
+
+	cr := &countingReader{r: bytes.NewReader(rawBlock)}
+	var header wire.BlockHeader
+	if err := header.Deserialize(cr); err != nil {
+		return &DecodeError{Stage: "block", Offset: cr.n, Err: err}
+	}
+	txCount, err := wire.ReadVarInt(cr, 0)
+	if err != nil {
+		return &DecodeError{Stage: "block", Offset: cr.n, Err: err}
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for i := uint64(0); i < txCount; i++ {
+		txReply, err := fromReader(cr, cfg)
+		if err != nil {
+			return &DecodeError{Stage: "block", Offset: cr.n, Err: err}
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(txReply); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// ComputeMerkleRoot returns the merkle root of the txids of txs, in block
+// order, as committed to by the block header. Witnesses are not part of this
+// root. As in Bitcoin Core, a level with an odd number of nodes pairs its last
+// node with itself, and no transaction yields the zero hash.
+func ComputeMerkleRoot(txs []TxRawDecodeResult) chainhash.Hash {
+	if len(txs) == 0 {
+		return chainhash.Hash{}
+	}
+
+	level := make([]chainhash.Hash, len(txs))
+	for i, tx := range txs {
+		level[i] = tx.Hash()
+	}
+	var pair [chainhash.HashSize * 2]byte
+	for len(level) > 1 {
+		if len(level)%2 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		for i := 0; i < len(level); i += 2 {
+			copy(pair[:chainhash.HashSize], level[i][:])
+			copy(pair[chainhash.HashSize:], level[i+1][:])
+			level[i/2] = chainhash.DoubleHashH(pair[:])
+		}
+		level = level[:len(level)/2]
+	}
+	return level[0]
+}
+
+// VerifyMerkleRoot reports whether the merkle root of header matches the one
+// computed from txs by ComputeMerkleRoot. Because of the odd node rule, a
+// list whose trailing transactions are repeated may match the root of the
+// list without them, so a match does not rule out duplicated transactions.
+func VerifyMerkleRoot(header BlockHeaderResult, txs []TxRawDecodeResult) bool {
+	root, err := chainhash.NewHashFromStr(header.MerkleRoot)
+	if err != nil {
+		return false
+	}
+	return ComputeMerkleRoot(txs) == *root
+}
+
+// newBlockHeaderResult builds the result for a deserialized block header.
+func newBlockHeaderResult(h *wire.BlockHeader) BlockHeaderResult {
+	return BlockHeaderResult{
+		Hash:       h.BlockHash().String(),
+		Version:    h.Version,
+		PrevBlock:  h.PrevBlock.String(),
+		MerkleRoot: h.MerkleRoot.String(),
+		Time:       h.Timestamp.Unix(),
+		Bits:       strconv.FormatInt(int64(h.Bits), 16),
+		Nonce:      h.Nonce,
+	}
+}
@@ -0,0 +1,67 @@
+package rawdecodebtc
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// TestBlockFromHexGenesis checks the header and transaction decoded from the
+// mainnet genesis block against their well-known values.
+func TestBlockFromHexGenesis(t *testing.T) {
+	header, txs, err := BlockFromHex(genesisBlockHex, "mainnet")
+	if err != nil {
+		t.Fatalf("BlockFromHex: %v", err)
+	}
+
+	want := BlockHeaderResult{
+		Hash:       "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+		Version:    1,
+		PrevBlock:  "0000000000000000000000000000000000000000000000000000000000000000",
+		MerkleRoot: "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		Time:       1231006505,
+		Bits:       "1d00ffff",
+		Nonce:      2083236893,
+	}
+	if header != want {
+		t.Errorf("header %+v, want %+v", header, want)
+	}
+
+	if len(txs) != 1 {
+		t.Fatalf("%d transactions, want 1", len(txs))
+	}
+	if txs[0].Txid != want.MerkleRoot {
+		t.Errorf("coinbase txid %s, want %s", txs[0].Txid, want.MerkleRoot)
+	}
+	if !txs[0].IsCoinbase {
+		t.Error("first transaction not reported as a coinbase")
+	}
+}
+
+// TestComputeMerkleRoot checks the merkle root computed for mainnet block 170
+// and its two transactions against the one of its header.
+func TestComputeMerkleRoot(t *testing.T) {
+	header, txs, err := BlockFromHex(block170Hex, "mainnet")
+	if err != nil {
+		t.Fatalf("BlockFromHex: %v", err)
+	}
+
+	const wantRoot = "7dac2c5666815c17a3b36427de37bb9d2e2c5ccec3f8633eb91a4205cb4c10ff"
+	if header.MerkleRoot != wantRoot {
+		t.Fatalf("header merkle root %s, want %s", header.MerkleRoot, wantRoot)
+	}
+	if got := ComputeMerkleRoot(txs); got.String() != wantRoot {
+		t.Errorf("ComputeMerkleRoot %s, want %s", got, wantRoot)
+	}
+	if !VerifyMerkleRoot(header, txs) {
+		t.Error("VerifyMerkleRoot rejected the block transactions")
+	}
+
+	swapped := []TxRawDecodeResult{txs[1], txs[0]}
+	if VerifyMerkleRoot(header, swapped) {
+		t.Error("VerifyMerkleRoot accepted the transactions out of order")
+	}
+	if got := ComputeMerkleRoot(nil); !got.IsEqual(&chainhash.Hash{}) {
+		t.Errorf("ComputeMerkleRoot of no transaction %s, want the zero hash", got)
+	}
+}
@@ -0,0 +1,113 @@
+package rawdecodebtc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// DecodeBlockFile decodes the blocks stored in r in the format of the
+// blk*.dat files of Bitcoin Core, a sequence of records each made of the
+// network magic, the block length as a little endian uint32 and the raw
+// block. fn is called with every block, decoded as by BlockFromHex, in file
+// order, and decoding stops with the error fn returns, if any.
+//
+// Bytes between records, such as the zero padding preallocated at the end of
+// a file, are skipped up to the next magic of the network, and so is a magic
+// followed by a length no block can have. A record cut short or holding a
+// malformed block fails with a DecodeError whose offset counts from the start
+// of r, wrapping io.ErrUnexpectedEOF in the former case.
+func DecodeBlockFile(r io.Reader, net string, fn func(header BlockHeaderResult, txs []TxRawDecodeResult) error) error {
+	cparam, err := paramsForNet(net)
+	if err != nil {
+		return err
+	}
+
+	var magic [4]byte
+	binary.LittleEndian.PutUint32(magic[:], uint32(cparam.Net))
+
+	br := bufio.NewReader(r)
+	var offset int64
+	for {
+		skipped, err := skipToMagic(br, magic)
+		offset += skipped
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		offset += int64(len(magic))
+
+		var sizeBuf [4]byte
+		if _, err := io.ReadFull(br, sizeBuf[:]); err != nil {
+			return truncatedRecordError(offset, err)
+		}
+		offset += int64(len(sizeBuf))
+		size := binary.LittleEndian.Uint32(sizeBuf[:])
+		if size < blockHeaderLen || size > wire.MaxBlockPayload {
+			continue
+		}
+
+		rawBlock := make([]byte, size)
+		if n, err := io.ReadFull(br, rawBlock); err != nil {
+			return truncatedRecordError(offset+int64(n), err)
+		}
+
+		header, txs, err := decodeBlock(rawBlock, cparam)
+		if err != nil {
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				decodeErr.Offset += offset
+			}
+			return err
+		}
+		offset += int64(size)
+
+		if err := fn(header, txs); err != nil {
+			return err
+		}
+	}
+}
+
+// blockHeaderLen is the serialized size of a block header, the least a block
+// record can hold.
+const blockHeaderLen = 80
+
+// truncatedRecordError reports a block file record cut short after offset
+// bytes of the file were read.
+func truncatedRecordError(offset int64, err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return &DecodeError{Stage: "block", Offset: offset, Err: err}
+}
+
+// skipToMagic consumes br up to and including the next occurrence of magic
+// and returns the number of bytes skipped before it. It returns io.EOF when
+// br ends first. The network magics hold four distinct bytes, so a partial
+// match can only restart at the byte that broke it.
+func skipToMagic(br *bufio.Reader, magic [4]byte) (int64, error) {
+	var skipped int64
+	matched := 0
+	for matched < len(magic) {
+		b, err := br.ReadByte()
+		if err != nil {
+			return skipped + int64(matched), err
+		}
+		switch {
+		case b == magic[matched]:
+			matched++
+		case b == magic[0]:
+			skipped += int64(matched)
+			matched = 1
+		default:
+			skipped += int64(matched) + 1
+			matched = 0
+		}
+	}
+	return skipped, nil
+}
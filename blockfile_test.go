@@ -0,0 +1,68 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+)
+
+// blockRecord frames the raw block of blockHex as a mainnet blk*.dat record.
+func blockRecord(t *testing.T, blockHex string) []byte {
+	t.Helper()
+	rawBlock, err := hex.DecodeString(blockHex)
+	if err != nil {
+		t.Fatalf("decoding block hex: %v", err)
+	}
+	record := []byte{0xf9, 0xbe, 0xb4, 0xd9, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(record[4:], uint32(len(rawBlock)))
+	return append(record, rawBlock...)
+}
+
+// TestDecodeBlockFile checks that the blocks of a blk*.dat stream are decoded
+// in order across the junk found between records: a partial magic, a magic
+// followed by a length no block can have and trailing zero padding.
+func TestDecodeBlockFile(t *testing.T) {
+	var file bytes.Buffer
+	file.Write(blockRecord(t, genesisBlockHex))
+	file.Write([]byte{0xf9, 0xbe, 0x00, 0x01})
+	file.Write([]byte{0xf9, 0xbe, 0xb4, 0xd9, 0x10, 0x00, 0x00, 0x00})
+	file.Write(blockRecord(t, block170Hex))
+	file.Write(make([]byte, 64))
+
+	var hashes []string
+	err := DecodeBlockFile(&file, "mainnet",
+		func(header BlockHeaderResult, txs []TxRawDecodeResult) error {
+			if !VerifyMerkleRoot(header, txs) {
+				t.Errorf("block %s: merkle root mismatch", header.Hash)
+			}
+			hashes = append(hashes, header.Hash)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("DecodeBlockFile: %v", err)
+	}
+
+	want := []string{
+		"000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+		"00000000d1145790a8694403d4063f323d499e655c83426834d4ce2f8dd4a2ee",
+	}
+	if len(hashes) != len(want) {
+		t.Fatalf("decoded blocks %v, want %v", hashes, want)
+	}
+	for i := range want {
+		if hashes[i] != want[i] {
+			t.Errorf("block %d: hash %s, want %s", i, hashes[i], want[i])
+		}
+	}
+
+	truncated := blockRecord(t, block170Hex)
+	truncated = truncated[:len(truncated)-10]
+	err = DecodeBlockFile(bytes.NewReader(truncated), "mainnet",
+		func(BlockHeaderResult, []TxRawDecodeResult) error { return nil })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated record: error %v, want io.ErrUnexpectedEOF", err)
+	}
+}
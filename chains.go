@@ -1,8 +1,95 @@
 package rawdecodebtc
 
-import "github.com/btcsuite/btcd/chaincfg"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrUnknownNetwork is returned when a network name is not one of "mainnet",
+// "testnet", "regtest" or "signet".
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// paramsForNet returns the chain parameters matching the given network name.
+func paramsForNet(net string) (*chaincfg.Params, error) {
+	switch net {
+	case "mainnet":
+		return mainnet, nil
+	case "regtest":
+		return regtest, nil
+	case "testnet":
+		return testnet, nil
+	case "signet":
+		return signet, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownNetwork, net)
+	}
+}
+
+// networkNames lists the network names accepted by paramsForNet, in the
+// order FilterNetworks reports them.
+var networkNames = []string{"mainnet", "testnet", "signet", "regtest"}
+
+// FilterNetworks returns the networks under which the raw transaction rawHex
+// pays every address of knownAddrs, in the order "mainnet", "testnet",
+// "signet", "regtest". A raw transaction does not embed its network and its
+// output scripts read the same on every network, so the addresses, known from
+// where the transaction was found, are the only evidence: with none of them
+// every network is returned.
+//
+// Networks sharing address prefixes cannot be told apart this way: testnet
+// and signet encode every address alike, and regtest shares their legacy
+// prefixes. An empty list means that no network pays all of knownAddrs.
+func FilterNetworks(rawHex string, knownAddrs ...string) ([]string, error) {
+	rawTx, err := HexDecodeRawTxString(rawHex)
+	if err != nil {
+		return nil, err
+	}
+	mtx, _, err := deserializeRawTx(rawTx, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]string, 0, len(networkNames))
+	for _, net := range networkNames {
+		chainParams, _ := paramsForNet(net)
+		if paysAddresses(mtx, chainParams, knownAddrs) {
+			candidates = append(candidates, net)
+		}
+	}
+	return candidates, nil
+}
+
+// paysAddresses reports whether every address of addrs is paid by an output
+// of mtx, with output addresses encoded under chainParams.
+func paysAddresses(mtx *wire.MsgTx, chainParams *chaincfg.Params, addrs []string) bool {
+	if len(addrs) == 0 {
+		return true
+	}
+
+	paid := make(map[string]struct{})
+	for _, txOut := range mtx.TxOut {
+		_, outAddrs, _, _ := txscript.ExtractPkScriptAddrs(txOut.PkScript,
+			chainParams)
+		for _, addr := range encodeAddresses(outAddrs, nil) {
+			paid[addr] = struct{}{}
+		}
+	}
+	for _, addr := range addrs {
+		if _, ok := paid[addr]; !ok {
+			return false
+		}
+	}
+	return true
+}
 
 var regtest = &chaincfg.Params{
+	// Message start magic, also used to frame records in block files.
+	Net: wire.TestNet,
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "bcrt", // always bcrt for reg test net
@@ -22,6 +109,9 @@ var regtest = &chaincfg.Params{
 }
 
 var mainnet = &chaincfg.Params{
+	// Message start magic, also used to frame records in block files.
+	Net: wire.MainNet,
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "bc", // always bc for main net
@@ -43,6 +133,9 @@ var mainnet = &chaincfg.Params{
 }
 
 var testnet = &chaincfg.Params{
+	// Message start magic, also used to frame records in block files.
+	Net: wire.TestNet3,
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "tb", // always tb for test net
@@ -62,3 +155,29 @@ var testnet = &chaincfg.Params{
 	// address generation.
 	HDCoinType: 1,
 }
+
+// signetMagic is the message start magic of the default signet, which the
+// wire package predates.
+const signetMagic wire.BitcoinNet = 0x40cf030a
+
+var signet = &chaincfg.Params{
+	// Message start magic, also used to frame records in block files.
+	Net: signetMagic,
+
+	// Human-readable part for Bech32 encoded segwit addresses, as defined in
+	// BIP 173.
+	Bech32HRPSegwit: "tb", // always tb for sig net
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x6f, // starts with m or n
+	ScriptHashAddrID: 0xc4, // starts with 2
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
+}
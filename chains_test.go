@@ -0,0 +1,59 @@
+package rawdecodebtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+// p2wpkhTxHex is a testnet transaction with a single P2WPKH output.
+const p2wpkhTxHex = "020000000105726534a113256d00b118d2d268a9bffc7190f345a8359fd08f7b1da51319503200000000ffffffff0160d45101000000001600149f65c37acdff7d5ec131e05bc24509685edb669a00000000"
+
+// TestFilterNetworks checks the networks kept by FilterNetworks for the
+// addresses known to be paid by a transaction.
+func TestFilterNetworks(t *testing.T) {
+	tests := []struct {
+		name       string
+		knownAddrs []string
+		want       []string
+	}{
+		{
+			name: "no evidence",
+			want: []string{"mainnet", "testnet", "signet", "regtest"},
+		},
+		{
+			name:       "mainnet bech32",
+			knownAddrs: []string{"bc1qnajux7kdla74asf3upduy3gfdp0dke56sku8sd"},
+			want:       []string{"mainnet"},
+		},
+		{
+			name:       "testnet bech32",
+			knownAddrs: []string{"tb1qnajux7kdla74asf3upduy3gfdp0dke566s85t7"},
+			want:       []string{"testnet", "signet"},
+		},
+		{
+			name:       "regtest bech32",
+			knownAddrs: []string{"bcrt1qnajux7kdla74asf3upduy3gfdp0dke56ce7euh"},
+			want:       []string{"regtest"},
+		},
+		{
+			name:       "unrelated address",
+			knownAddrs: []string{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+			want:       []string{},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := FilterNetworks(p2wpkhTxHex, test.knownAddrs...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	if _, err := FilterNetworks("zz"); err == nil {
+		t.Error("invalid hex: expected an error")
+	}
+}
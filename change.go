@@ -0,0 +1,89 @@
+package rawdecodebtc
+
+// roundValueSat is the granularity, in satoshi, at which an output value is
+// considered round and more likely to be a payment than change.
+const roundValueSat = 10000
+
+// inputAddressTypes maps the input types of Vin.InputType to the address type
+// of the output they spend.
+var inputAddressTypes = map[string]string{
+	InputP2PK:           AddressP2PK,
+	InputP2PKH:          AddressP2PKH,
+	InputP2SH:           AddressP2SH,
+	InputP2SHP2WPKH:     AddressP2SH,
+	InputP2SHP2WSH:      AddressP2SH,
+	InputP2WPKH:         AddressP2WPKH,
+	InputP2WSH:          AddressP2WSH,
+	InputP2TRKeyPath:    AddressP2TR,
+	InputP2TRScriptPath: AddressP2TR,
+}
+
+// GuessChangeOutput returns the index of the output of r most likely paying
+// change back to the sender, by the following heuristics applied in order to
+// the outputs that are not OP_RETURN:
+//
+//   - When every input spends the same known address type, only the outputs
+//     of that type are kept, wallets paying change to their own script type.
+//   - When several outputs remain, those whose value is a multiple of 10000
+//     satoshi are dropped, payments commonly being round amounts.
+//
+// The boolean is false when no single output remains, when the transaction
+// is a coinbase or has fewer than two candidate outputs, or when r was
+// decoded with an address filter, so the guess is only made when the rules
+// single one out. Being a heuristic, it can be wrong even then.
+func GuessChangeOutput(r TxRawDecodeResult) (int, bool) {
+	if r.IsCoinbase || (r.mtx != nil && len(r.Vout) != len(r.mtx.TxOut)) {
+		return 0, false
+	}
+
+	var candidates []Vout
+	for _, vout := range r.Vout {
+		if vout.AddressType != AddressNullData {
+			candidates = append(candidates, vout)
+		}
+	}
+	if len(candidates) < 2 {
+		return 0, false
+	}
+
+	if inputType, ok := commonInputAddressType(r.Vin); ok {
+		candidates = filterVouts(candidates, func(vout Vout) bool {
+			return vout.AddressType == inputType
+		})
+	}
+	if len(candidates) > 1 {
+		candidates = filterVouts(candidates, func(vout Vout) bool {
+			return vout.ValueSat%roundValueSat != 0
+		})
+	}
+
+	if len(candidates) != 1 {
+		return 0, false
+	}
+	return int(candidates[0].N), true
+}
+
+// commonInputAddressType returns the address type spent by every input of
+// vins, when they all spend the same known type.
+func commonInputAddressType(vins []Vin) (string, bool) {
+	var common string
+	for i, vin := range vins {
+		addrType, ok := inputAddressTypes[vin.InputType]
+		if !ok || (i > 0 && addrType != common) {
+			return "", false
+		}
+		common = addrType
+	}
+	return common, common != ""
+}
+
+// filterVouts returns the outputs of vouts for which keep returns true.
+func filterVouts(vouts []Vout, keep func(Vout) bool) []Vout {
+	var kept []Vout
+	for _, vout := range vouts {
+		if keep(vout) {
+			kept = append(kept, vout)
+		}
+	}
+	return kept
+}
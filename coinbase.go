@@ -0,0 +1,156 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// maxHeightPushLen is the largest height push accepted by
+// CoinbaseBlockHeight. Three bytes cover every height up to 8388607, while
+// pre-BIP34 coinbases commonly start with a four byte push of the block bits.
+const maxHeightPushLen = 3
+
+// CoinbaseBlockHeight returns the block height encoded at the start of the
+// coinbase signature script as required by BIP34. The boolean is false when
+// mtx is not a coinbase or its script does not start with a valid height,
+// which is the case for blocks mined before BIP34 activated.
+func CoinbaseBlockHeight(mtx *wire.MsgTx) (int32, bool) {
+	if !blockchain.IsCoinBaseTx(mtx) {
+		return 0, false
+	}
+	height, _, ok := parseHeightPush(mtx.TxIn[0].SignatureScript)
+	return height, ok
+}
+
+// parseHeightPush decodes the minimally encoded script number pushed by the
+// first opcode of script, and returns the length of that push.
+func parseHeightPush(script []byte) (int32, int, bool) {
+	if len(script) == 0 {
+		return 0, 0, false
+	}
+
+	op := script[0]
+	if op >= txscript.OP_1 && op <= txscript.OP_16 {
+		return int32(op - (txscript.OP_1 - 1)), 1, true
+	}
+
+	// Anything else must be a small direct data push.
+	pushLen := int(op)
+	if pushLen == 0 || pushLen > maxHeightPushLen || len(script) < 1+pushLen {
+		return 0, 0, false
+	}
+	data := script[1 : 1+pushLen]
+
+	// Reject negative numbers and non-minimal encodings, neither of which
+	// a BIP34 compliant miner produces.
+	last := data[pushLen-1]
+	if last&0x80 != 0 {
+		return 0, 0, false
+	}
+	if last == 0 && (pushLen == 1 || data[pushLen-2]&0x80 == 0) {
+		return 0, 0, false
+	}
+
+	var height int32
+	for i := pushLen - 1; i >= 0; i-- {
+		height = height<<8 | int32(data[i])
+	}
+	return height, 1 + pushLen, true
+}
+
+// minCoinbaseTagRun is the shortest run of printable characters kept in a
+// coinbase tag, shorter runs being most likely random extranonce bytes.
+const minCoinbaseTagRun = 4
+
+// CoinbaseExtraData returns the arbitrary data following the BIP34 height in
+// the coinbase signature script, which usually holds the extranonce and a
+// miner tag, along with that tag as a best effort ASCII string: the runs of
+// at least four printable ASCII characters of the data, joined by spaces.
+// The whole script is returned when it does not start with a valid height,
+// as in blocks mined before BIP34 activated. The boolean is false when mtx
+// is not a coinbase.
+func CoinbaseExtraData(mtx *wire.MsgTx) (data []byte, tag string, ok bool) {
+	if !blockchain.IsCoinBaseTx(mtx) {
+		return nil, "", false
+	}
+
+	data = mtx.TxIn[0].SignatureScript
+	if _, n, ok := parseHeightPush(data); ok {
+		data = data[n:]
+	}
+	return data, printableRuns(data, minCoinbaseTagRun), true
+}
+
+// printableRuns returns the runs of at least minRun printable ASCII
+// characters of data, joined by spaces.
+func printableRuns(data []byte, minRun int) string {
+	var runs []string
+	start := 0
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && data[i] >= 0x20 && data[i] <= 0x7e {
+			continue
+		}
+		if i-start >= minRun {
+			runs = append(runs, string(data[start:i]))
+		}
+		start = i + 1
+	}
+	return strings.Join(runs, " ")
+}
+
+// witnessCommitmentHeader prefixes the BIP141 witness commitment output
+// script: OP_RETURN, a 36 byte push and the 0xaa21a9ed commitment tag.
+var witnessCommitmentHeader = []byte{0x6a, 0x24, 0xaa, 0x21, 0xa9, 0xed}
+
+// witnessCommitmentLen is the length of witnessCommitmentHeader followed by
+// the 32 byte commitment hash.
+const witnessCommitmentLen = 38
+
+// WitnessCommitment returns the 32 byte BIP141 witness commitment of a
+// coinbase transaction. As per BIP141 the last output matching the
+// commitment pattern wins. The boolean is false when mtx is not a coinbase
+// or carries no commitment.
+func WitnessCommitment(mtx *wire.MsgTx) ([]byte, bool) {
+	if !blockchain.IsCoinBaseTx(mtx) {
+		return nil, false
+	}
+
+	for i := len(mtx.TxOut) - 1; i >= 0; i-- {
+		pkScript := mtx.TxOut[i].PkScript
+		if isWitnessCommitmentScript(pkScript) {
+			return pkScript[len(witnessCommitmentHeader):witnessCommitmentLen], true
+		}
+	}
+	return nil, false
+}
+
+// isWitnessCommitmentScript reports whether pkScript matches the BIP141
+// witness commitment pattern.
+func isWitnessCommitmentScript(pkScript []byte) bool {
+	return len(pkScript) >= witnessCommitmentLen &&
+		bytes.HasPrefix(pkScript, witnessCommitmentHeader)
+}
+
+// witnessReservedLen is the length of the BIP141 witness reserved value.
+const witnessReservedLen = 32
+
+// CoinbaseWitnessReserved returns the 32 byte BIP141 witness reserved value
+// carried as the only witness element of a coinbase input, which is hashed
+// with the witness merkle root into the commitment returned by
+// WitnessCommitment. The boolean is false when mtx is not a coinbase or its
+// witness does not hold a single 32 byte element.
+func CoinbaseWitnessReserved(mtx *wire.MsgTx) ([]byte, bool) {
+	if !blockchain.IsCoinBaseTx(mtx) {
+		return nil, false
+	}
+
+	witness := mtx.TxIn[0].Witness
+	if len(witness) != 1 || len(witness[0]) != witnessReservedLen {
+		return nil, false
+	}
+	return witness[0], true
+}
@@ -0,0 +1,54 @@
+package rawdecodebtc
+
+import (
+	"strings"
+	"testing"
+)
+
+// coinbase277647TxHex is the coinbase of mainnet block 277647, mined by BTC
+// Guild after BIP34 activated.
+const coinbase277647TxHex = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff53038f3c040400003d8b45124d696e656420627920425443204775696c642cfabe6d6d180ec2f9a5ff672bb0b3df6e14703defe4b6570194be38428122c0b001c5445b010000000000000008000008d700000dceffffffff014b424b95000000001976a91427a1f12771de5cc3b73941664b2537c15316be4388ac00000000"
+
+// TestCoinbaseBlockHeight checks the BIP34 height read from coinbases mined
+// before and after its activation.
+func TestCoinbaseBlockHeight(t *testing.T) {
+	_, genesisTxs, err := BlockFromHex(genesisBlockHex, "mainnet")
+	if err != nil {
+		t.Fatalf("BlockFromHex: %v", err)
+	}
+	txReply, err := FromHex(coinbase277647TxHex, "mainnet")
+	if err != nil {
+		t.Fatalf("FromHex: %v", err)
+	}
+	legacyReply, err := FromHex(legacyTxHex, "testnet")
+	if err != nil {
+		t.Fatalf("FromHex: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		txReply    TxRawDecodeResult
+		wantHeight int32
+		wantOK     bool
+	}{
+		{name: "block 277647", txReply: txReply, wantHeight: 277647, wantOK: true},
+		{name: "genesis, before BIP34", txReply: genesisTxs[0]},
+		{name: "not a coinbase", txReply: legacyReply},
+	}
+
+	for _, test := range tests {
+		height, ok := CoinbaseBlockHeight(test.txReply.MsgTx())
+		if height != test.wantHeight || ok != test.wantOK {
+			t.Errorf("%s: got %d %v, want %d %v", test.name, height, ok,
+				test.wantHeight, test.wantOK)
+		}
+		if test.txReply.CoinbaseHeight != test.wantHeight {
+			t.Errorf("%s: CoinbaseHeight %d, want %d", test.name,
+				test.txReply.CoinbaseHeight, test.wantHeight)
+		}
+	}
+
+	if !strings.Contains(txReply.CoinbaseTag, "Mined by BTC Guild") {
+		t.Errorf("CoinbaseTag %q lacks the miner tag", txReply.CoinbaseTag)
+	}
+}
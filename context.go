@@ -0,0 +1,59 @@
+package rawdecodebtc
+
+import (
+	"context"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// MaxTxSize is the default largest serialized transaction, in bytes, read by
+// FromReaderContext. No valid transaction can exceed the largest block
+// message.
+const MaxTxSize = wire.MaxBlockPayload
+
+// FromReaderContext decodes a single raw transaction read from r like
+// FromReader, but stops early when ctx is done or more bytes would be read
+// than allowed by WithMaxTxSize, MaxTxSize by default. The returned error
+// then wraps ctx.Err() or ErrTxTooLarge. opts further configure the decoding
+// as for Decode.
+func FromReaderContext(ctx context.Context, r io.Reader, net string, opts ...Option) (txReply TxRawDecodeResult, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	cfg, err := newDecodeConfig(append([]Option{WithNetwork(net)}, opts...))
+	if err != nil {
+		return
+	}
+
+	return fromReader(&boundedReader{
+		ctx:       ctx,
+		r:         r,
+		remaining: int64(cfg.txSizeLimit()),
+	}, cfg)
+}
+
+// boundedReader fails reads once its context is done or its byte budget is
+// exhausted.
+type boundedReader struct {
+	ctx       context.Context
+	r         io.Reader
+	remaining int64
+}
+
+func (b *boundedReader) Read(p []byte) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if b.remaining <= 0 {
+		return 0, ErrTxTooLarge
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.r.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,44 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// TestFromReaderContextMaxTxSize checks that WithMaxTxSize bounds the bytes
+// read from the stream.
+func TestFromReaderContextMaxTxSize(t *testing.T) {
+	rawTx, err := hex.DecodeString(legacyTxHex)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "default"},
+		{name: "exact size", opts: []Option{WithMaxTxSize(len(rawTx))}},
+		{
+			name:    "too small",
+			opts:    []Option{WithMaxTxSize(len(rawTx) - 1)},
+			wantErr: ErrTxTooLarge,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := FromReaderContext(context.Background(),
+			bytes.NewReader(rawTx), "testnet", test.opts...)
+		if test.wantErr == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.wantErr)
+		}
+	}
+}
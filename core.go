@@ -0,0 +1,176 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// CoreTxResult models a decoded transaction as returned by the
+// decoderawtransaction RPC of Bitcoin Core, for callers replacing that call.
+// It follows the output shape of Bitcoin Core v22 and later, where each
+// output carries a single address, less the desc descriptor added in v23.
+type CoreTxResult struct {
+	Txid     string        `json:"txid"`
+	Hash     string        `json:"hash"`
+	Version  int32         `json:"version"`
+	Size     int           `json:"size"`
+	Vsize    int           `json:"vsize"`
+	Weight   int64         `json:"weight"`
+	Locktime uint32        `json:"locktime"`
+	Vin      []btcjson.Vin `json:"vin"`
+	Vout     []CoreVout    `json:"vout"`
+}
+
+// CoreVout models a transaction output as returned by decoderawtransaction.
+type CoreVout struct {
+	Value        float64          `json:"value"`
+	N            uint32           `json:"n"`
+	ScriptPubKey CoreScriptPubKey `json:"scriptPubKey"`
+}
+
+// CoreScriptPubKey models an output script as returned by
+// decoderawtransaction. Type uses the script type names of Bitcoin Core,
+// such as "witness_v1_taproot". Address is only set for the P2PKH, P2SH and
+// segwit version 0 outputs the btcutil in use can encode, as it lacks the
+// bech32m encoding of taproot addresses.
+type CoreScriptPubKey struct {
+	Asm     string `json:"asm"`
+	Hex     string `json:"hex"`
+	Address string `json:"address,omitempty"`
+	Type    string `json:"type"`
+}
+
+// CoreResult returns the Bitcoin Core shaped view of the decoded
+// transaction. Fields specific to this package are left out.
+func (r TxRawDecodeResult) CoreResult() CoreTxResult {
+	vin := make([]btcjson.Vin, len(r.Vin))
+	for i, v := range r.Vin {
+		vin[i] = v.Btcjson()
+	}
+
+	vout := make([]CoreVout, len(r.Vout))
+	for i, v := range r.Vout {
+		pkScript, _ := hex.DecodeString(v.ScriptPubKey.Hex)
+		scriptPubKey := CoreScriptPubKey{
+			Asm:  v.ScriptPubKey.Asm,
+			Hex:  v.ScriptPubKey.Hex,
+			Type: coreScriptType(pkScript),
+		}
+		switch scriptPubKey.Type {
+		case "pubkeyhash", "scripthash", "witness_v0_keyhash",
+			"witness_v0_scripthash":
+			if len(v.ScriptPubKey.Addresses) == 1 {
+				scriptPubKey.Address = v.ScriptPubKey.Addresses[0]
+			}
+		}
+		vout[i] = CoreVout{
+			Value:        btcutil.Amount(v.ValueSat).ToBTC(),
+			N:            v.N,
+			ScriptPubKey: scriptPubKey,
+		}
+	}
+
+	return CoreTxResult{
+		Txid:     r.Txid,
+		Hash:     r.Wtxid,
+		Version:  r.Version,
+		Size:     r.SerializeSize,
+		Vsize:    r.Vsize,
+		Weight:   r.Weight,
+		Locktime: r.Locktime,
+		Vin:      vin,
+		Vout:     vout,
+	}
+}
+
+// payToAnchorProgram is the witness version 1 program of pay-to-anchor
+// outputs, reported as "anchor" since Bitcoin Core v28.
+var payToAnchorProgram = []byte{0x4e, 0x73}
+
+// coreScriptType returns the Bitcoin Core name of the type of pkScript. The
+// btcd script classes use the same names, except for the segwit version 1
+// and later programs it classifies as nonstandard.
+func coreScriptType(pkScript []byte) string {
+	scriptClass := txscript.GetScriptClass(pkScript)
+	if scriptClass != txscript.NonStandardTy {
+		return scriptClass.String()
+	}
+
+	version, program, err := txscript.ExtractWitnessProgramInfo(pkScript)
+	switch {
+	case err != nil || version == 0:
+		return scriptClass.String()
+	case version == 1 && len(program) == 32:
+		return "witness_v1_taproot"
+	case version == 1 && bytes.Equal(program, payToAnchorProgram):
+		return "anchor"
+	default:
+		return "witness_unknown"
+	}
+}
+
+// CoreJSON returns the JSON encoding of CoreResult, matching the output of
+// decoderawtransaction.
+func (r TxRawDecodeResult) CoreJSON() ([]byte, error) {
+	return json.Marshal(r.CoreResult())
+}
+
+// FromRPCResult decodes the raw transaction returned by the
+// getrawtransaction RPC. jsonBytes may be the verbose result object, whose
+// hex field is decoded, the JSON string of the non-verbose result, the whole
+// JSON-RPC response wrapping either of them, or the bare hex itself. The
+// error wraps ErrNoTxHex when no transaction hex is found, and carries the
+// message of an RPC error response.
+func FromRPCResult(jsonBytes []byte, net string) (txReply TxRawDecodeResult, err error) {
+	message, err := rpcResultHex(jsonBytes)
+	if err != nil {
+		return
+	}
+	return FromHex(message, net)
+}
+
+// rpcResultHex extracts the transaction hex from an RPC result as accepted
+// by FromRPCResult.
+func rpcResultHex(b []byte) (string, error) {
+	b = bytes.TrimSpace(b)
+	switch {
+	case len(b) == 0:
+		return "", ErrNoTxHex
+	case b[0] != '{' && b[0] != '[' && b[0] != '"':
+		// Not JSON, leave the hex check to the decoder.
+		return string(b), nil
+	}
+
+	var message string
+	if err := json.Unmarshal(b, &message); err == nil {
+		return message, nil
+	}
+
+	var result struct {
+		Hex    *string         `json:"hex"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoTxHex, err)
+	}
+	switch {
+	case result.Hex != nil:
+		return *result.Hex, nil
+	case result.Error != nil:
+		return "", fmt.Errorf("%w: rpc error %d: %s", ErrNoTxHex,
+			result.Error.Code, result.Error.Message)
+	case len(result.Result) > 0 && string(result.Result) != "null":
+		return rpcResultHex(result.Result)
+	}
+	return "", ErrNoTxHex
+}
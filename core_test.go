@@ -0,0 +1,70 @@
+package rawdecodebtc
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestCoreScriptType checks the Bitcoin Core names of output script types,
+// including the segwit version 1 programs btcd classifies as nonstandard.
+func TestCoreScriptType(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkScript string
+		want     string
+	}{
+		{
+			name:     "p2pkh",
+			pkScript: "76a914a7e32aaf8d24bf138be271ade0e135328f6e335a88ac",
+			want:     "pubkeyhash",
+		},
+		{
+			name:     "p2wpkh",
+			pkScript: "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+			want:     "witness_v0_keyhash",
+		},
+		{
+			name: "p2tr",
+			pkScript: "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2" +
+				"815b16f81798",
+			want: "witness_v1_taproot",
+		},
+		{name: "pay to anchor", pkScript: "51024e73", want: "anchor"},
+		{name: "witness version 2", pkScript: "52024e73", want: "witness_unknown"},
+		{name: "op_true", pkScript: "51", want: "nonstandard"},
+	}
+
+	for _, test := range tests {
+		pkScript, err := hex.DecodeString(test.pkScript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := coreScriptType(pkScript); got != test.want {
+			t.Errorf("%s: got type %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+// TestCoreResult checks the Bitcoin Core view of a legacy transaction, whose
+// output reports a single address.
+func TestCoreResult(t *testing.T) {
+	txReply, err := FromHex(legacyTxHex, "testnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	core := txReply.CoreResult()
+	if core.Hash != txReply.Wtxid || core.Vsize != txReply.Vsize {
+		t.Errorf("got hash %s and vsize %d, want %s and %d", core.Hash,
+			core.Vsize, txReply.Wtxid, txReply.Vsize)
+	}
+	vout := core.Vout[0]
+	if vout.Value != 0.03847171 {
+		t.Errorf("got value %v, want 0.03847171", vout.Value)
+	}
+	if vout.ScriptPubKey.Address != "mvpfGupqZWA39pujo6H6DjbziY2SZNDra2" ||
+		vout.ScriptPubKey.Type != "pubkeyhash" {
+		t.Errorf("got scriptPubKey %+v, want the P2PKH address",
+			vout.ScriptPubKey)
+	}
+}
@@ -1,12 +1,21 @@
 package rawdecodebtc
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
@@ -14,187 +23,847 @@ import (
 
 // TxRawDecodeResult models the data from the decoderawtransaction command.
 type TxRawDecodeResult struct {
-	Txid                  string         `json:"txid"`
-	Version               int32          `json:"version"`
-	Locktime              uint32         `json:"locktime"`
-	SerializeSizeStripped int            `json:"sizestripped"`
-	SerializeSize         int            `json:"size"`
-	Vin                   []btcjson.Vin  `json:"vin"`
-	Vout                  []btcjson.Vout `json:"vout"`
+	Txid                  string     `json:"txid"`
+	Wtxid                 string     `json:"wtxid"`
+	Version               int32      `json:"version"`
+	Locktime              uint32     `json:"locktime"`
+	LocktimeType          string     `json:"locktimetype"`
+	LocktimeTime          *time.Time `json:"locktimetime,omitempty"`
+	SerializeSizeStripped int        `json:"sizestripped"`
+	SerializeSize         int        `json:"size"`
+	Weight                int64      `json:"weight"`
+	Vsize                 int        `json:"vsize"`
+	HasWitness            bool       `json:"haswitness"`
+
+	// HasSegwitMarker reports whether the serialization carried the segwit
+	// marker and flag bytes, which it may do even when every witness is
+	// empty. It is false when the transaction was not decoded from its own
+	// bytes, as with FromWire, DecodeWire, blocks and PSBTs.
+	HasSegwitMarker bool `json:"hassegwitmarker"`
+
+	IsCoinbase     bool  `json:"iscoinbase"`
+	CoinbaseHeight int32 `json:"coinbaseheight,omitempty"`
+
+	// CoinbaseTag is the miner tag of a coinbase, see CoinbaseExtraData.
+	CoinbaseTag string `json:"coinbasetag,omitempty"`
+
+	Bip125Replaceable bool `json:"bip125-replaceable"`
+
+	// Version2Features reports whether the version enforces the BIP68
+	// relative locktimes of input sequences, see RelativeLockEnforced. Below
+	// version 2 sequences only signal replaceability, and no input reports a
+	// RelativeLock.
+	Version2Features bool `json:"version2features"`
+
+	// SigOps is the legacy signature operation count, see SigOpCount.
+	SigOps int `json:"sigops"`
+
+	Vin      []Vin   `json:"vin"`
+	Vout     []Vout  `json:"vout"`
+	TotalOut float64 `json:"totalout"`
+	TotalIn  float64 `json:"totalin,omitempty"`
+
+	// Fee is the fee paid, in the amount unit, when the values of the spent
+	// outputs are known, see FromHexWithPrevouts. It is nil otherwise, so that
+	// a zero fee is told from an unknown one.
+	Fee *float64 `json:"fee,omitempty"`
+
+	FeeRate   float64  `json:"feerate,omitempty"`
+	Anomalies []string `json:"anomalies,omitempty"`
+
+	mtx *wire.MsgTx
 }
 
-//FromMessage decodes raw transaction from raw payload
-func FromMessage(rawTx []byte, net string) (txReply TxRawDecodeResult, err error) {
-	var cparam *chaincfg.Params
-	switch net {
-	case "regtest":
-		cparam = regtest
-	case "testnet":
-		cparam = testnet
-	default:
-		cparam = mainnet
+// Vin models a transaction input of the decoded result. It carries the same
+// fields as btcjson.Vin plus the ones specific to this package.
+type Vin struct {
+	Coinbase string `json:"coinbase,omitempty"`
+	Txid     string `json:"txid,omitempty"`
+	Vout     uint32 `json:"vout"`
+
+	// Outpoint is the spent outpoint formatted as "<txid>:<vout>", empty for
+	// a coinbase.
+	Outpoint string `json:"outpoint,omitempty"`
+
+	ScriptSig *btcjson.ScriptSig `json:"scriptSig,omitempty"`
+	Witness   []string           `json:"txinwitness,omitempty"`
+	Sequence  uint32             `json:"sequence"`
+
+	// ScriptError holds the error hit while disassembling the scriptSig, if
+	// any.
+	ScriptError string `json:"scripterror,omitempty"`
+
+	// InputType is the kind of output spent as inferred from the scriptSig
+	// and witness, see classifyInput.
+	InputType string `json:"inputtype"`
+
+	// RedeemScriptAsm and WitnessScriptAsm disassemble the redeem or witness
+	// script revealed when the input spends a P2SH or P2WSH output, see
+	// RedeemScript and WitnessScript for how they are recognized.
+	RedeemScriptAsm  string `json:"redeemscriptasm,omitempty"`
+	WitnessScriptAsm string `json:"witnessscriptasm,omitempty"`
+
+	// RelativeLock is the BIP68 relative locktime of the sequence, when
+	// enabled and enforced by the transaction version.
+	RelativeLock *SequenceInfo `json:"relativelock,omitempty"`
+
+	// ScriptSigLen is the length in bytes of the scriptSig, or of the
+	// coinbase script.
+	ScriptSigLen int `json:"scriptsiglen"`
+
+	// NullInput flags a non-coinbase input spending the all-zero outpoint
+	// hash reserved for coinbases, see AnomalyNullPrevout.
+	NullInput bool `json:"nullinput"`
+
+	// Value and PrevScriptType, the script type of the spent output as
+	// reported in ScriptPubKey.Type, are only set when decoding with
+	// WithUTXOProvider.
+	Value          float64 `json:"value,omitempty"`
+	PrevScriptType string  `json:"prevscripttype,omitempty"`
+
+	// HasAnnex and Annex, the annex in hex including its 0x50 tag, are only
+	// set for taproot inputs, see ParseTaprootWitness.
+	HasAnnex bool   `json:"hasannex,omitempty"`
+	Annex    string `json:"annex,omitempty"`
+
+	// SignatureScript is the raw scriptSig, only set when decoding with
+	// WithRawScripts. It is shared with the decoded transaction.
+	SignatureScript []byte `json:"-"`
+}
+
+// IsCoinBase returns whether the input is a coinbase input.
+func (v Vin) IsCoinBase() bool {
+	return len(v.Coinbase) > 0
+}
+
+// Btcjson returns the input as the btcjson.Vin used by the result before it
+// got its own Vin type, dropping the fields specific to this package, for
+// callers still built around btcjson.
+func (v Vin) Btcjson() btcjson.Vin {
+	return btcjson.Vin{
+		Coinbase:  v.Coinbase,
+		Txid:      v.Txid,
+		Vout:      v.Vout,
+		ScriptSig: v.ScriptSig,
+		Sequence:  v.Sequence,
+		Witness:   v.Witness,
 	}
+}
 
-	r := strings.NewReader(string(rawTx))
-	var mtx wire.MsgTx
-	err = mtx.Deserialize(r)
+// MarshalJSON implements json.Marshaler. Like btcjson.Vin, coinbase inputs
+// are marshalled without the previous output index.
+func (v Vin) MarshalJSON() ([]byte, error) {
+	type vin Vin
+	if !v.IsCoinBase() {
+		return json.Marshal(vin(v))
+	}
+
+	// The outer field shadows the embedded one and is always omitted.
+	return json.Marshal(struct {
+		vin
+		Vout *uint32 `json:"vout,omitempty"`
+	}{vin: vin(v)})
+}
+
+// Vout models a transaction output of the decoded result. It carries the
+// same fields as btcjson.Vout plus the ones specific to this package.
+type Vout struct {
+	Value float64 `json:"value"`
+
+	// ValueSat is the value in satoshi.
+	ValueSat int64 `json:"valuesat"`
+
+	N            uint32                     `json:"n"`
+	ScriptPubKey btcjson.ScriptPubKeyResult `json:"scriptPubKey"`
+
+	// AddressType is the conventional label of the script type, such as
+	// P2WPKH or P2TR, next to the class name kept in ScriptPubKey.Type.
+	AddressType string `json:"addresstype"`
+
+	// ReqSigsKnown tells whether ScriptPubKey.ReqSigs is exact for the script
+	// type, see reqSigsKnown.
+	ReqSigsKnown bool `json:"reqsigsknown"`
+
+	// IsDust tells whether the output is dust at DefaultRelayFeePerKb.
+	IsDust bool `json:"isdust"`
+
+	// ScriptError holds the error hit while disassembling the scriptPubKey,
+	// if any.
+	ScriptError string `json:"scripterror,omitempty"`
+
+	// PkScriptLen is the length of the scriptPubKey in bytes.
+	PkScriptLen int `json:"pkscriptlen"`
+
+	// DataCarrierSize is the size of the data carried by an OP_RETURN
+	// output, see the function of the same name.
+	DataCarrierSize int `json:"datacarriersize,omitempty"`
+
+	// IsOpReturn tells whether the scriptPubKey starts with OP_RETURN.
+	IsOpReturn bool `json:"isopreturn"`
+
+	// IsWitnessCommitment tells whether a coinbase output matches the BIP141
+	// witness commitment pattern.
+	IsWitnessCommitment bool `json:"iswitnesscommitment"`
+
+	// AltAddresses, only set when decoding with WithAltAddresses, holds the
+	// other addresses of the same key or script: the P2WPKH and P2SH-P2WPKH
+	// forms of a P2PKH output or of a P2PK output with a compressed key, the
+	// P2PKH and P2SH-P2WPKH forms of a P2WPKH output and the P2SH-P2WSH form
+	// of a P2WSH output. Forms derived from a key hash assume the key is
+	// compressed, as segwit requires.
+	AltAddresses []string `json:"altaddresses,omitempty"`
+
+	// PkScript is the raw scriptPubKey, only set when decoding with
+	// WithRawScripts. It is shared with the decoded transaction.
+	PkScript []byte `json:"-"`
+}
+
+// Btcjson returns the output as the btcjson.Vout used by the result before
+// it got its own Vout type, with the value in BTC whatever the amount unit
+// and dropping the fields specific to this package, for callers still built
+// around btcjson.
+func (v Vout) Btcjson() btcjson.Vout {
+	return btcjson.Vout{
+		Value:        btcutil.Amount(v.ValueSat).ToBTC(),
+		N:            v.N,
+		ScriptPubKey: v.ScriptPubKey,
+	}
+}
+
+// MsgTx returns the deserialized transaction the result was built from, or
+// nil for a result that was not produced by this package. It is shared with
+// the result and must not be modified.
+func (r TxRawDecodeResult) MsgTx() *wire.MsgTx {
+	return r.mtx
+}
+
+// ToHex serializes the decoded transaction back to its raw hex encoding.
+func (r TxRawDecodeResult) ToHex() (string, error) {
+	if r.mtx == nil {
+		return "", errors.New("no transaction attached to result")
+	}
+	return EncodeToHex(r.mtx)
+}
+
+// Decode decodes the raw transaction input as configured by opts. Without
+// options input holds the raw bytes of a mainnet transaction.
+func Decode(input []byte, opts ...Option) (txReply TxRawDecodeResult, err error) {
+	cfg, err := newDecodeConfig(opts)
 	if err != nil {
 		return
 	}
 
-	// Create and return the result.
-	txReply = TxRawDecodeResult{
-		Txid:                  mtx.TxHash().String(),
-		Version:               mtx.Version,
-		Locktime:              mtx.LockTime,
-		SerializeSize:         mtx.SerializeSize(),
-		SerializeSizeStripped: mtx.SerializeSizeStripped(),
-		Vin:                   CreateVinList(&mtx),
-		Vout:                  CreateVoutList(&mtx, cparam, nil),
+	if cfg.hexInput {
+		input, err = HexDecodeRawTxString(string(input))
+		if err != nil {
+			return
+		}
+	}
+
+	return decodeRaw(input, cfg)
+}
+
+// decodeRaw deserializes the raw transaction rawTx and builds its result.
+func decodeRaw(rawTx []byte, cfg *decodeConfig) (txReply TxRawDecodeResult, err error) {
+	start := cfg.startStage()
+	mtx, marker, err := deserializeRawTx(rawTx, cfg.noWitness,
+		cfg.allowTrailing)
+	cfg.endStage(StageDeserialize, start)
+	if err != nil {
+		if cfg.partial {
+			txReply = partialResult(rawTx, cfg)
+		}
+		return
 	}
+
+	txReply, err = decodeMsgTx(mtx, cfg)
+	txReply.HasSegwitMarker = marker
 	return
 }
 
-//FromWire decodes wire msg
-func FromWire(mtx *wire.MsgTx, net string) (txReply TxRawDecodeResult, err error) {
-	var cparam *chaincfg.Params
-	switch net {
-	case "regtest":
-		cparam = regtest
-	case "testnet":
-		cparam = testnet
-	default:
-		cparam = mainnet
-	}
-
-	// Create and return the result.
-	txReply = TxRawDecodeResult{
-		Txid:                  mtx.TxHash().String(),
-		Version:               mtx.Version,
-		Locktime:              mtx.LockTime,
-		SerializeSize:         mtx.SerializeSize(),
-		SerializeSizeStripped: mtx.SerializeSizeStripped(),
-		Vin:                   CreateVinList(mtx),
-		Vout:                  CreateVoutList(mtx, cparam, nil),
+// decodeMsgTx builds the result for an already deserialized transaction and
+// applies the configured post-processing.
+func decodeMsgTx(mtx *wire.MsgTx, cfg *decodeConfig) (txReply TxRawDecodeResult, err error) {
+	if mtx == nil {
+		err = ErrNilTx
+		return
+	}
+	if cfg.maxInputs > 0 && len(mtx.TxIn) > cfg.maxInputs {
+		err = fmt.Errorf("%w: %d exceeds limit of %d", ErrTooManyInputs,
+			len(mtx.TxIn), cfg.maxInputs)
+		return
+	}
+	if cfg.maxOutputs > 0 && len(mtx.TxOut) > cfg.maxOutputs {
+		err = fmt.Errorf("%w: %d exceeds limit of %d", ErrTooManyOutputs,
+			len(mtx.TxOut), cfg.maxOutputs)
+		return
+	}
+	if cfg.moneyRange {
+		if err = checkMoneyRange(mtx); err != nil {
+			return
+		}
+	}
+
+	txReply = buildResult(mtx, cfg)
+	switch {
+	case cfg.utxoProvider != nil:
+		err = annotateInputs(&txReply, mtx, cfg.utxoProvider, cfg.amountUnit)
+	case cfg.prevouts != nil:
+		err = setFee(&txReply, mtx, cfg.prevouts, cfg.amountUnit)
 	}
 	return
 }
 
-//FromHex decodes raw transaction from Hex payload
-func FromHex(message string, net string) (txReply TxRawDecodeResult, err error) {
-	hexDecodedTx, err := HexDecodeRawTxString(message)
+// FromMessage decodes raw transaction from raw payload
+func FromMessage(rawTx []byte, net string) (txReply TxRawDecodeResult, err error) {
+	return Decode(rawTx, WithNetwork(net))
+}
+
+// FromMessageFiltered decodes raw transaction from raw payload, keeping only
+// the outputs paying one of addrs. An empty or nil filter keeps all outputs.
+func FromMessageFiltered(rawTx []byte, net string, addrs []string) (txReply TxRawDecodeResult, err error) {
+	return Decode(rawTx, WithNetwork(net), WithFilterAddrs(addrs))
+}
 
-	var cparam *chaincfg.Params
-	switch net {
-	case "regtest":
-		cparam = regtest
-	case "testnet":
-		cparam = testnet
-	default:
-		cparam = mainnet
+// FromMessageWithParams decodes raw transaction from raw payload using the
+// caller supplied chain parameters.
+func FromMessageWithParams(rawTx []byte, params *chaincfg.Params) (txReply TxRawDecodeResult, err error) {
+	return Decode(rawTx, WithParams(params))
+}
+
+// FromReader decodes a single raw transaction read from r. Only the bytes of
+// the transaction are consumed, so it can be called repeatedly to decode
+// transactions concatenated in one stream.
+func FromReader(r io.Reader, net string) (txReply TxRawDecodeResult, err error) {
+	cfg, err := newDecodeConfig([]Option{WithNetwork(net)})
+	if err != nil {
+		return
 	}
 
-	r := strings.NewReader(string(hexDecodedTx))
+	return fromReader(r, cfg)
+}
+
+func fromReader(r io.Reader, cfg *decodeConfig) (txReply TxRawDecodeResult, err error) {
+	start := cfg.startStage()
+	mtx, marker, err := deserializeTx(r, cfg.noWitness)
+	cfg.endStage(StageDeserialize, start)
+	if err != nil {
+		return
+	}
+
+	txReply, err = decodeMsgTx(mtx, cfg)
+	txReply.HasSegwitMarker = marker
+	return
+}
+
+// deserializeTx reads a single transaction from r, wrapping failures in a
+// DecodeError, and reports whether it was framed with the segwit marker and
+// flag. When noWitness is set the legacy serialization format is assumed and
+// no segwit marker is looked for.
+func deserializeTx(r io.Reader, noWitness bool) (*wire.MsgTx, bool, error) {
+	head := &prefixBuffer{max: segwitHeaderLen}
+	cr := &countingReader{r: io.TeeReader(r, head)}
 	var mtx wire.MsgTx
-	err = mtx.Deserialize(r)
+	var err error
+	if noWitness {
+		err = mtx.DeserializeNoWitness(cr)
+	} else {
+		err = mtx.Deserialize(cr)
+	}
+	if err != nil {
+		return nil, false, &DecodeError{Stage: "transaction", Offset: cr.n, Err: err}
+	}
+	return &mtx, !noWitness && hasSegwitMarker(head.buf), nil
+}
+
+// deserializeRawTx deserializes the raw transaction rawTx as deserializeTx
+// does. Unless allowTrailing is set, bytes left over after the transaction
+// fail with an error wrapping ErrTrailingBytes. A transaction failing to
+// deserialize in the segwit format is retried in the legacy format, like
+// Bitcoin Core does, since a legacy transaction without inputs reads as a
+// segwit marker. The first error is returned when both fail, unless rawTx
+// looks like an Elements transaction, see looksLikeElements, which fails with
+// an error wrapping ErrUnsupportedFormat instead.
+func deserializeRawTx(rawTx []byte, noWitness, allowTrailing bool) (*wire.MsgTx, bool, error) {
+	mtx, marker, err := deserializeWhole(rawTx, noWitness, allowTrailing)
+	if err == nil {
+		return mtx, marker, nil
+	}
+
+	if !noWitness {
+		legacy, _, legacyErr := deserializeWhole(rawTx, true, allowTrailing)
+		if legacyErr == nil {
+			return legacy, false, nil
+		}
+	}
+	if looksLikeElements(rawTx) {
+		err = fmt.Errorf("%w: Elements transaction (%v)",
+			ErrUnsupportedFormat, err)
+	}
+	return nil, false, err
+}
+
+// deserializeWhole deserializes a single transaction from rawTx as
+// deserializeTx does, checking that it spans all of rawTx unless
+// allowTrailing is set.
+func deserializeWhole(rawTx []byte, noWitness, allowTrailing bool) (*wire.MsgTx, bool, error) {
+	r := bytes.NewReader(rawTx)
+	mtx, marker, err := deserializeTx(r, noWitness)
+	if err != nil || allowTrailing || r.Len() == 0 {
+		return mtx, marker, err
+	}
+
+	return nil, false, &DecodeError{
+		Stage:  "transaction",
+		Offset: int64(len(rawTx) - r.Len()),
+		Err:    fmt.Errorf("%w: %d extra bytes", ErrTrailingBytes, r.Len()),
+	}
+}
+
+// segwitHeaderLen is the length of the version, marker and flag fields that
+// open a segwit serialized transaction.
+const segwitHeaderLen = 6
+
+// hasSegwitMarker reports whether the serialized transaction header head
+// holds the segwit marker 0x00 and flag 0x01 after the version, where a
+// legacy transaction has its input count.
+func hasSegwitMarker(head []byte) bool {
+	return len(head) >= segwitHeaderLen && head[4] == 0x00 && head[5] == 0x01
+}
+
+// prefixBuffer keeps the first max bytes written to it and discards the
+// rest.
+type prefixBuffer struct {
+	buf []byte
+	max int
+}
+
+func (b *prefixBuffer) Write(p []byte) (int, error) {
+	if n := b.max - len(b.buf); n > 0 {
+		if n > len(p) {
+			n = len(p)
+		}
+		b.buf = append(b.buf, p[:n]...)
+	}
+	return len(p), nil
+}
+
+// ValidateHex reports whether message is a well formed raw transaction for
+// the given network, without building the decode result. It returns the same
+// errors as FromHex.
+func ValidateHex(message string, net string) error {
+	if _, err := paramsForNet(net); err != nil {
+		return err
+	}
+
+	hexDecodedTx, err := HexDecodeRawTxString(message)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = deserializeRawTx(hexDecodedTx, false, false)
+	return err
+}
+
+// LegacyTxid returns the txid, the hash of the serialization without
+// witnesses, of the raw transaction message in hex. It skips building the
+// decode result, for callers such as mempool feeds mapping wtxids to txids
+// at volume. It returns the same errors as FromHex.
+func LegacyTxid(message string) (string, error) {
+	hexDecodedTx, err := HexDecodeRawTxString(message)
+	if err != nil {
+		return "", err
+	}
+
+	mtx, _, err := deserializeRawTx(hexDecodedTx, false, false)
+	if err != nil {
+		return "", err
+	}
+	return mtx.TxHash().String(), nil
+}
+
+// VerifySizes checks that the raw transaction message in hex is exactly as
+// long as its canonical serialization. A difference reveals nonstandard
+// framing, such as trailing bytes or a segwit marker with empty witnesses,
+// and is reported with an error wrapping ErrSizeMismatch. Decoding failures
+// are returned as by FromHex.
+func VerifySizes(message string) error {
+	hexDecodedTx, err := HexDecodeRawTxString(message)
+	if err != nil {
+		return err
+	}
+
+	mtx, _, err := deserializeRawTx(hexDecodedTx, false, true)
+	if err != nil {
+		return err
+	}
+	if size := mtx.SerializeSize(); size != len(hexDecodedTx) {
+		return fmt.Errorf("%w: input is %d bytes, serializes to %d",
+			ErrSizeMismatch, len(hexDecodedTx), size)
+	}
+	return nil
+}
+
+// FromWire decodes wire msg
+func FromWire(mtx *wire.MsgTx, net string) (txReply TxRawDecodeResult, err error) {
+	return fromWire(mtx, WithNetwork(net))
+}
+
+// FromWireFiltered decodes wire msg, keeping only the outputs paying one of
+// addrs. An empty or nil filter keeps all outputs.
+func FromWireFiltered(mtx *wire.MsgTx, net string, addrs []string) (txReply TxRawDecodeResult, err error) {
+	return fromWire(mtx, WithNetwork(net), WithFilterAddrs(addrs))
+}
+
+// FromWireWithParams decodes wire msg using the caller supplied chain
+// parameters.
+func FromWireWithParams(mtx *wire.MsgTx, params *chaincfg.Params) (txReply TxRawDecodeResult, err error) {
+	return fromWire(mtx, WithParams(params))
+}
+
+func fromWire(mtx *wire.MsgTx, opts ...Option) (txReply TxRawDecodeResult, err error) {
+	cfg, err := newDecodeConfig(opts)
 	if err != nil {
 		return
 	}
 
-	// Create and return the result.
-	txReply = TxRawDecodeResult{
+	return decodeMsgTx(mtx, cfg)
+}
+
+// FromHex decodes raw transaction from Hex payload
+func FromHex(message string, net string) (txReply TxRawDecodeResult, err error) {
+	return Decode([]byte(message), WithNetwork(net), WithHexInput())
+}
+
+// FromHexFiltered decodes raw transaction from Hex payload, keeping only the
+// outputs paying one of addrs. An empty or nil filter keeps all outputs.
+func FromHexFiltered(message string, net string, addrs []string) (txReply TxRawDecodeResult, err error) {
+	return Decode([]byte(message), WithNetwork(net), WithHexInput(),
+		WithFilterAddrs(addrs))
+}
+
+// FromHexNoWitness decodes raw transaction from Hex payload serialized in the
+// legacy format, without the segwit marker and flag. Bytes that would
+// otherwise be taken for a segwit marker are read as transaction data and
+// the inputs never carry a witness.
+func FromHexNoWitness(message string, net string) (txReply TxRawDecodeResult, err error) {
+	return Decode([]byte(message), WithNetwork(net), WithHexInput(),
+		withNoWitness())
+}
+
+// FromHexWithParams decodes raw transaction from Hex payload using the caller
+// supplied chain parameters, for chains not covered by the network names.
+func FromHexWithParams(message string, params *chaincfg.Params) (txReply TxRawDecodeResult, err error) {
+	return Decode([]byte(message), WithParams(params), WithHexInput())
+}
+
+// SafeFromHex decodes raw transaction from Hex payload as FromHex does, but
+// recovers from any panic raised while decoding untrusted input and returns
+// it as an error wrapping ErrDecodePanic.
+func SafeFromHex(message string, net string) (txReply TxRawDecodeResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			txReply = TxRawDecodeResult{}
+			err = fmt.Errorf("%w: %v", ErrDecodePanic, r)
+		}
+	}()
+
+	return FromHex(message, net)
+}
+
+// FromBase64 decodes raw transaction from standard base64 payload. Failures to
+// decode the base64 wrap ErrInvalidBase64.
+func FromBase64(message string, net string) (txReply TxRawDecodeResult, err error) {
+	rawTx, err := base64.StdEncoding.DecodeString(strings.TrimSpace(message))
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+		return
+	}
+
+	return FromMessage(rawTx, net)
+}
+
+// FromHexPrefixed decodes raw transaction from Hex payload prefixed with its
+// length as a CompactSize, as transactions appear in some P2P messages and
+// dumps. The declared length must match both the bytes following the prefix
+// and the size of the transaction read from them, or the error wraps
+// ErrLengthMismatch.
+func FromHexPrefixed(message string, net string) (txReply TxRawDecodeResult, err error) {
+	raw, err := HexDecodeRawTxString(message)
+	if err != nil {
+		return
+	}
+
+	r := bytes.NewReader(raw)
+	size, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		offset := r.Size() - int64(r.Len())
+		err = &DecodeError{Stage: "length prefix", Offset: offset, Err: err}
+		return
+	}
+	if size != uint64(r.Len()) {
+		err = fmt.Errorf("%w: prefix declares %d bytes, %d follow",
+			ErrLengthMismatch, size, r.Len())
+		return
+	}
+
+	txReply, err = Decode(raw[len(raw)-r.Len():], WithNetwork(net),
+		WithTrailingBytes())
+	if err != nil {
+		return
+	}
+	if uint64(txReply.SerializeSize) != size {
+		err = fmt.Errorf("%w: prefix declares %d bytes, transaction is %d",
+			ErrLengthMismatch, size, txReply.SerializeSize)
+		txReply = TxRawDecodeResult{}
+	}
+	return
+}
+
+// newTxRawDecodeResult builds the result for an already deserialized
+// transaction. Filtered out outputs keep their original index in Vout.
+func newTxRawDecodeResult(mtx *wire.MsgTx, chainParams *chaincfg.Params, filterAddrMap map[string]struct{}) TxRawDecodeResult {
+	return buildResult(mtx, &decodeConfig{
+		params:        chainParams,
+		filterAddrMap: filterAddrMap,
+	})
+}
+
+// buildResult builds the result for an already deserialized transaction as
+// configured by cfg.
+func buildResult(mtx *wire.MsgTx, cfg *decodeConfig) TxRawDecodeResult {
+	coinbaseHeight, _ := CoinbaseBlockHeight(mtx)
+	_, coinbaseTag, _ := CoinbaseExtraData(mtx)
+	locktimeType, locktimeTime := locktimeInfo(mtx)
+
+	start := cfg.startStage()
+	vinList := createVinList(mtx, cfg)
+	cfg.endStage(StageVin, start)
+
+	start = cfg.startStage()
+	voutList := createVoutList(mtx, cfg)
+	cfg.endStage(StageVout, start)
+
+	return TxRawDecodeResult{
 		Txid:                  mtx.TxHash().String(),
+		Wtxid:                 mtx.WitnessHash().String(),
 		Version:               mtx.Version,
 		Locktime:              mtx.LockTime,
+		LocktimeType:          locktimeType,
+		LocktimeTime:          locktimeTime,
 		SerializeSize:         mtx.SerializeSize(),
 		SerializeSizeStripped: mtx.SerializeSizeStripped(),
-		Vin:                   CreateVinList(&mtx),
-		Vout:                  CreateVoutList(&mtx, cparam, nil),
+		Weight:                blockchain.GetTransactionWeight(btcutil.NewTx(mtx)),
+		Vsize:                 int(virtualSize(mtx)),
+		HasWitness:            mtx.HasWitness(),
+		IsCoinbase:            blockchain.IsCoinBaseTx(mtx),
+		CoinbaseHeight:        coinbaseHeight,
+		CoinbaseTag:           coinbaseTag,
+		Bip125Replaceable:     signalsReplacement(mtx),
+		Version2Features:      RelativeLockEnforced(mtx.Version),
+		SigOps:                SigOpCount(mtx),
+		Vin:                   vinList,
+		Vout:                  voutList,
+		TotalOut:              totalOut(mtx).ToUnit(cfg.amountUnit),
+		Anomalies:             detectAnomalies(mtx, cfg),
+		mtx:                   mtx,
 	}
-	return
+}
+
+// totalOut sums the value of every output of the transaction, regardless of
+// any address filter applied to Vout.
+func totalOut(mtx *wire.MsgTx) btcutil.Amount {
+	var total int64
+	for _, txOut := range mtx.TxOut {
+		total += txOut.Value
+	}
+	return btcutil.Amount(total)
+}
+
+// filterMap converts a list of addresses into the set used by CreateVoutList.
+// It returns nil for an empty list so that no filtering takes place.
+func filterMap(addrs []string) map[string]struct{} {
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	m := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		m[addr] = struct{}{}
+	}
+	return m
+}
+
+// AddressFilter builds the filter set taken by CreateVoutList from addrs, as
+// WithFilterAddrs does, after checking that every entry is an address of the
+// given network or a P2SH or P2WSH script hash in lowercase hex. The error
+// wraps ErrInvalidAddress for the first entry that is neither, or
+// ErrUnknownNetwork. Like WithFilterAddrs it returns nil for an empty list so
+// that no filtering takes place.
+func AddressFilter(net string, addrs ...string) (map[string]struct{}, error) {
+	chainParams, err := paramsForNet(net)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		if !validFilterEntry(addr, chainParams) {
+			return nil, fmt.Errorf("%w for %s: %q", ErrInvalidAddress, net, addr)
+		}
+	}
+	return filterMap(addrs), nil
+}
+
+// validFilterEntry reports whether entry is an address of chainParams or a
+// script hash as matched by scriptHashInFilter.
+func validFilterEntry(entry string, chainParams *chaincfg.Params) bool {
+	if addr, err := btcutil.DecodeAddress(entry, chainParams); err == nil {
+		return addr.IsForNet(chainParams)
+	}
+
+	scriptHash, err := hex.DecodeString(entry)
+	if err != nil || entry != strings.ToLower(entry) {
+		return false
+	}
+	return len(scriptHash) == 20 || len(scriptHash) == 32
 }
 
 // CreateVinList returns a slice of JSON objects for the inputs of the passed
-// transaction.
-func CreateVinList(mtx *wire.MsgTx) []btcjson.Vin {
+// transaction. Use Vin.Btcjson for the btcjson.Vin it used to return.
+func CreateVinList(mtx *wire.MsgTx) []Vin {
+	return createVinList(mtx, &decodeConfig{})
+}
+
+func createVinList(mtx *wire.MsgTx, cfg *decodeConfig) []Vin {
 	// Coinbase transactions only have a single txin by definition.
-	vinList := make([]btcjson.Vin, len(mtx.TxIn))
+	vinList := make([]Vin, len(mtx.TxIn))
 	if blockchain.IsCoinBaseTx(mtx) {
 		txIn := mtx.TxIn[0]
 		vinList[0].Coinbase = hex.EncodeToString(txIn.SignatureScript)
 		vinList[0].Sequence = txIn.Sequence
 		vinList[0].Witness = witnessToHex(txIn.Witness)
+		vinList[0].InputType = classifyInput(txIn, true)
+		vinList[0].ScriptSigLen = len(txIn.SignatureScript)
+		if cfg.rawScripts {
+			vinList[0].SignatureScript = txIn.SignatureScript
+		}
 		return vinList
 	}
 
 	for i, txIn := range mtx.TxIn {
 		// The disassembled string will contain [error] inline
-		// if the script doesn't fully parse, so ignore the
-		// error here.
-		disbuf, _ := txscript.DisasmString(txIn.SignatureScript)
+		// if the script doesn't fully parse, so the error is
+		// only kept for reference.
+		disbuf, disErr := txscript.DisasmString(txIn.SignatureScript)
 
 		vinEntry := &vinList[i]
 		vinEntry.Txid = txIn.PreviousOutPoint.Hash.String()
 		vinEntry.Vout = txIn.PreviousOutPoint.Index
+		vinEntry.Outpoint = txIn.PreviousOutPoint.String()
 		vinEntry.Sequence = txIn.Sequence
+		vinEntry.InputType = classifyInput(txIn, false)
+		if RelativeLockEnforced(mtx.Version) {
+			vinEntry.RelativeLock = relativeLock(txIn.Sequence)
+		}
+		vinEntry.ScriptSigLen = len(txIn.SignatureScript)
+		vinEntry.NullInput = txIn.PreviousOutPoint.Hash == chainhash.Hash{}
+		if cfg.rawScripts {
+			vinEntry.SignatureScript = txIn.SignatureScript
+		}
 		vinEntry.ScriptSig = &btcjson.ScriptSig{
 			Asm: disbuf,
 			Hex: hex.EncodeToString(txIn.SignatureScript),
 		}
+		if disErr != nil {
+			vinEntry.ScriptError = disErr.Error()
+		}
 
 		if mtx.HasWitness() {
 			vinEntry.Witness = witnessToHex(txIn.Witness)
 		}
+
+		if script, ok := RedeemScript(txIn.SignatureScript); ok {
+			vinEntry.RedeemScriptAsm, _ = txscript.DisasmString(script)
+		}
+		if script, ok := WitnessScript(txIn.Witness); ok {
+			vinEntry.WitnessScriptAsm, _ = txscript.DisasmString(script)
+		}
+		if isTaprootInput(vinEntry.InputType) {
+			info, _ := ParseTaprootWitness(txIn.Witness)
+			vinEntry.HasAnnex = info.Annex != nil
+			vinEntry.Annex = hex.EncodeToString(info.Annex)
+		}
 	}
 
 	return vinList
 }
 
 // CreateVoutList returns a slice of JSON objects for the outputs of the passed
-// transaction.
-func CreateVoutList(mtx *wire.MsgTx, chainParams *chaincfg.Params, filterAddrMap map[string]struct{}) []btcjson.Vout {
-	voutList := make([]btcjson.Vout, 0, len(mtx.TxOut))
+// transaction. Use Vout.Btcjson for the btcjson.Vout it used to return.
+func CreateVoutList(mtx *wire.MsgTx, chainParams *chaincfg.Params, filterAddrMap map[string]struct{}) []Vout {
+	return createVoutList(mtx, &decodeConfig{
+		params:        chainParams,
+		filterAddrMap: filterAddrMap,
+	})
+}
+
+func createVoutList(mtx *wire.MsgTx, cfg *decodeConfig) []Vout {
+	isCoinbase := blockchain.IsCoinBaseTx(mtx)
+	voutList := make([]Vout, 0, len(mtx.TxOut))
 	for i, v := range mtx.TxOut {
 		// The disassembled string will contain [error] inline if the
-		// script doesn't fully parse, so ignore the error here.
-		disbuf, _ := txscript.DisasmString(v.PkScript)
-
-		// Ignore the error here since an error means the script
-		// couldn't parse and there is no additional information about
-		// it anyways.
-		scriptClass, addrs, reqSigs, _ := txscript.ExtractPkScriptAddrs(
-			v.PkScript, chainParams)
-
-		// Encode the addresses while checking if the address passes the
-		// filter when needed.
-		passesFilter := len(filterAddrMap) == 0
-		encodedAddrs := make([]string, len(addrs))
-		for j, addr := range addrs {
-			encodedAddr := addr.EncodeAddress()
-			encodedAddrs[j] = encodedAddr
-
-			// No need to check the map again if the filter already
-			// passes.
-			if passesFilter {
-				continue
-			}
-			if _, exists := filterAddrMap[encodedAddr]; exists {
-				passesFilter = true
-			}
-		}
-
-		if !passesFilter {
+		// script doesn't fully parse, so the error is only kept for
+		// reference.
+		disbuf, disErr := txscript.DisasmString(v.PkScript)
+
+		var scriptClass txscript.ScriptClass
+		var encodedAddrs []string
+		var reqSigs int
+		if cfg.noAddresses && cfg.filterAddrMap == nil {
+			scriptClass = txscript.GetScriptClass(v.PkScript)
+		} else {
+			// Ignore the error here since an error means the
+			// script couldn't parse and there is no additional
+			// information about it anyways.
+			var addrs []btcutil.Address
+			scriptClass, addrs, reqSigs, _ = txscript.ExtractPkScriptAddrs(
+				v.PkScript, cfg.params)
+			encodedAddrs = encodeAddresses(addrs, cfg.addressEncoder)
+		}
+		if !passesFilter(encodedAddrs, cfg.filterAddrMap) &&
+			!scriptHashInFilter(scriptClass, v.PkScript, cfg.filterAddrMap) {
 			continue
 		}
+		if cfg.noAddresses {
+			encodedAddrs, reqSigs = nil, 0
+		}
 
-		var vout btcjson.Vout
+		var vout Vout
 		vout.N = uint32(i)
-		vout.Value = btcutil.Amount(v.Value).ToBTC()
+		vout.Value = btcutil.Amount(v.Value).ToUnit(cfg.amountUnit)
+		vout.ValueSat = v.Value
 		vout.ScriptPubKey.Addresses = encodedAddrs
 		vout.ScriptPubKey.Asm = disbuf
 		vout.ScriptPubKey.Hex = hex.EncodeToString(v.PkScript)
 		vout.ScriptPubKey.Type = scriptClass.String()
 		vout.ScriptPubKey.ReqSigs = int32(reqSigs)
+		vout.AddressType = addressType(scriptClass, v.PkScript)
+		vout.ReqSigsKnown = reqSigs > 0 && reqSigsKnown(scriptClass)
+		vout.IsDust = IsDustOutput(v, DefaultRelayFeePerKb)
+		vout.PkScriptLen = len(v.PkScript)
+		vout.DataCarrierSize, vout.IsOpReturn = DataCarrierSize(v.PkScript)
+		vout.IsWitnessCommitment = isCoinbase &&
+			isWitnessCommitmentScript(v.PkScript)
+		if cfg.altAddresses {
+			vout.AltAddresses = encodeAddresses(altAddresses(scriptClass,
+				v.PkScript, cfg.params), cfg.addressEncoder)
+		}
+		if cfg.rawScripts {
+			vout.PkScript = v.PkScript
+		}
+		if disErr != nil {
+			vout.ScriptError = disErr.Error()
+		}
 
 		voutList = append(voutList, vout)
 	}
@@ -220,10 +889,89 @@ func witnessToHex(witness wire.TxWitness) []string {
 }
 
 // HexDecodeRawTxString hex decodes a rawTx string and returns it as byte slice.
+// Whitespace anywhere in the string, as left by copying hex from a block
+// explorer, and a 0x or 0X prefix are ignored. Failures wrap ErrInvalidHex.
 func HexDecodeRawTxString(rawTx string) (hexDecodedTx []byte, err error) {
-	hexDecodedTx, err = hex.DecodeString(rawTx)
-	if err != nil {
-		return
+	return decodeHexInto(nil, rawTx)
+}
+
+// decodeHexInto hex decodes rawTx as HexDecodeRawTxString does, into dst
+// when it has the capacity to hold the result.
+func decodeHexInto(dst []byte, rawTx string) ([]byte, error) {
+	rawTx = normalizeHex(rawTx)
+	if len(rawTx)%2 != 0 {
+		return nil, fmt.Errorf("%w: raw tx hex has odd length %d, it may be truncated",
+			ErrInvalidHex, len(rawTx))
 	}
-	return
+
+	n := len(rawTx) / 2
+	if cap(dst) < n {
+		dst = make([]byte, n)
+	}
+	dst = dst[:n]
+	for i := range dst {
+		hi, ok := hexNibble(rawTx[2*i])
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHex,
+				hex.InvalidByteError(rawTx[2*i]))
+		}
+		lo, ok := hexNibble(rawTx[2*i+1])
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHex,
+				hex.InvalidByteError(rawTx[2*i+1]))
+		}
+		dst[i] = hi<<4 | lo
+	}
+	return dst, nil
+}
+
+// hexNibble returns the value of the hex digit c.
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// EncodeToHex serializes mtx and returns it hex encoded, the inverse of
+// HexDecodeRawTxString followed by deserialization.
+func EncodeToHex(mtx *wire.MsgTx) (string, error) {
+	var buf bytes.Buffer
+	buf.Grow(mtx.SerializeSize())
+	if err := mtx.Serialize(&buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf.Bytes()), nil
+}
+
+// StrippedBytes returns the serialization of mtx without any witness data,
+// the form hashed into the txid. For non-segwit transactions it equals the
+// full serialization, which is why their txid and wtxid are identical.
+func StrippedBytes(mtx *wire.MsgTx) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(mtx.SerializeSizeStripped())
+	if err := mtx.SerializeNoWitness(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeHex strips all whitespace and an optional 0x or 0X prefix from s.
+func normalizeHex(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	return s
 }
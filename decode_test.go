@@ -0,0 +1,207 @@
+package rawdecodebtc
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestWtxid checks that the wtxid matches the txid of a transaction without
+// witness only.
+func TestWtxid(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		wantEqual bool
+	}{
+		{name: "legacy", message: legacyTxHex, wantEqual: true},
+		{name: "segwit", message: segwitTxHex, wantEqual: false},
+	}
+
+	for _, test := range tests {
+		txReply, err := FromHex(test.message, "testnet")
+		if err != nil {
+			t.Fatalf("%s: FromHex: %v", test.name, err)
+		}
+		if txReply.Wtxid == "" {
+			t.Errorf("%s: empty wtxid", test.name)
+		}
+		if equal := txReply.Wtxid == txReply.Txid; equal != test.wantEqual {
+			t.Errorf("%s: wtxid %s, txid %s, want equal %v", test.name,
+				txReply.Wtxid, txReply.Txid, test.wantEqual)
+		}
+	}
+}
+
+// TestHasSegwitMarker checks that HasSegwitMarker follows the serialization
+// and is left false for a transaction handed over already deserialized.
+func TestHasSegwitMarker(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    bool
+	}{
+		{name: "legacy", message: legacyTxHex, want: false},
+		{name: "segwit", message: segwitTxHex, want: true},
+	}
+
+	for _, test := range tests {
+		txReply, err := FromHex(test.message, "testnet")
+		if err != nil {
+			t.Fatalf("%s: FromHex: %v", test.name, err)
+		}
+		if txReply.HasSegwitMarker != test.want {
+			t.Errorf("%s: HasSegwitMarker %v, want %v", test.name,
+				txReply.HasSegwitMarker, test.want)
+		}
+
+		wireReply, err := FromWire(txReply.MsgTx(), "testnet")
+		if err != nil {
+			t.Fatalf("%s: FromWire: %v", test.name, err)
+		}
+		if wireReply.HasSegwitMarker {
+			t.Errorf("%s: FromWire reported a segwit marker", test.name)
+		}
+	}
+}
+
+// hasAnomaly reports whether txReply carries the anomaly code.
+func hasAnomaly(txReply TxRawDecodeResult, code string) bool {
+	for _, anomaly := range txReply.Anomalies {
+		if anomaly == code {
+			return true
+		}
+	}
+	return false
+}
+
+// TestDecodeZeroInputs checks that transactions without inputs, which read
+// as a segwit marker, decode in the legacy format with an empty Vin.
+func TestDecodeZeroInputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		decode  func() (TxRawDecodeResult, error)
+		numVout int
+	}{
+		{
+			name: "hex with one output",
+			decode: func() (TxRawDecodeResult, error) {
+				return FromHex("010000000001e803000000000000015100000000",
+					"mainnet")
+			},
+			numVout: 1,
+		},
+		{
+			name: "hex without outputs",
+			decode: func() (TxRawDecodeResult, error) {
+				return FromHex("01000000000000000000", "mainnet")
+			},
+		},
+		{
+			name: "wire",
+			decode: func() (TxRawDecodeResult, error) {
+				return FromWire(wire.NewMsgTx(wire.TxVersion), "mainnet")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("panic: %v", r)
+				}
+			}()
+
+			txReply, err := test.decode()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if txReply.Vin == nil || len(txReply.Vin) != 0 {
+				t.Errorf("got Vin %v, want an empty list", txReply.Vin)
+			}
+			if len(txReply.Vout) != test.numVout {
+				t.Errorf("got %d outputs, want %d", len(txReply.Vout),
+					test.numVout)
+			}
+			if !hasAnomaly(txReply, AnomalyNoInputs) {
+				t.Errorf("got anomalies %v, want %s", txReply.Anomalies,
+					AnomalyNoInputs)
+			}
+		})
+	}
+}
+
+// TestNullInput checks that a non-coinbase input spending the all-zero
+// outpoint hash is flagged, while a real coinbase is not.
+func TestNullInput(t *testing.T) {
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	mtx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, 0), nil, nil))
+	mtx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+
+	txReply, err := FromWire(mtx, "mainnet")
+	if err != nil {
+		t.Fatalf("FromWire: %v", err)
+	}
+	if txReply.IsCoinbase {
+		t.Errorf("null prevout decoded as a coinbase")
+	}
+	if !txReply.Vin[0].NullInput {
+		t.Errorf("null prevout: got NullInput false, want true")
+	}
+	if !hasAnomaly(txReply, AnomalyNullPrevout) {
+		t.Errorf("null prevout: got anomalies %v, want %s",
+			txReply.Anomalies, AnomalyNullPrevout)
+	}
+
+	coinbase := chaincfg.MainNetParams.GenesisBlock.Transactions[0]
+	txReply, err = FromWire(coinbase, "mainnet")
+	if err != nil {
+		t.Fatalf("FromWire: %v", err)
+	}
+	if !txReply.IsCoinbase {
+		t.Errorf("genesis coinbase not decoded as a coinbase")
+	}
+	if txReply.Vin[0].NullInput {
+		t.Errorf("coinbase: got NullInput true, want false")
+	}
+	if hasAnomaly(txReply, AnomalyNullPrevout) {
+		t.Errorf("coinbase: got anomaly %s", AnomalyNullPrevout)
+	}
+}
+
+// FuzzFromHex checks that decoding arbitrary hex never panics and that every
+// transaction accepted gets its hashes computed.
+func FuzzFromHex(f *testing.F) {
+	f.Add(segwitTxHex)
+	f.Add(legacyTxHex)
+	f.Add("")
+	f.Add("0100000000000000000000")
+
+	f.Fuzz(func(t *testing.T, message string) {
+		txReply, err := FromHex(message, "testnet")
+		if err != nil {
+			return
+		}
+		if txReply.Txid == "" || txReply.Wtxid == "" {
+			t.Errorf("decoded transaction without hashes")
+		}
+	})
+}
+
+// FuzzFromPSBT checks that decoding arbitrary PSBT bytes never panics.
+func FuzzFromPSBT(f *testing.F) {
+	raw, err := base64.StdEncoding.DecodeString(bip174PSBT)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(raw)
+	f.Add([]byte("psbt\xff"))
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		FromPSBT(base64.StdEncoding.EncodeToString(raw), "testnet")
+	})
+}
@@ -0,0 +1,72 @@
+package rawdecodebtc
+
+import (
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// maxPooledRawTx is the capacity in bytes above which a hex decoding buffer
+// is not returned to the pool, so that an occasional huge transaction does
+// not pin its memory. 128 KiB holds all but the largest transactions.
+const maxPooledRawTx = 128 << 10
+
+// Decoder decodes transactions with a fixed configuration, resolved once
+// when it is created. It is safe for concurrent use by multiple goroutines.
+//
+// DecodeHex reuses the buffers it decodes hex into across calls, which saves
+// an allocation the size of the transaction per decode. Results never alias
+// those buffers: the deserialized transaction copies the scripts and
+// witnesses it reads, and each result keeps its own wire.MsgTx, returned by
+// its MsgTx method, so transactions themselves are not pooled.
+type Decoder struct {
+	cfg     *decodeConfig
+	rawBufs sync.Pool
+}
+
+// NewDecoder returns a Decoder configured by opts, which default to mainnet
+// as for Decode. WithHexInput has no effect, the input format being chosen
+// by the method called instead.
+func NewDecoder(opts ...Option) (*Decoder, error) {
+	cfg, err := newDecodeConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Decoder{cfg: cfg}, nil
+}
+
+// DecodeHex decodes raw transaction from Hex payload.
+func (d *Decoder) DecodeHex(message string) (TxRawDecodeResult, error) {
+	buf, _ := d.rawBufs.Get().(*[]byte)
+	if buf == nil {
+		buf = new([]byte)
+	}
+	defer d.putRawBuf(buf)
+
+	rawTx, err := decodeHexInto(*buf, message)
+	if err != nil {
+		return TxRawDecodeResult{}, err
+	}
+	*buf = rawTx
+	return decodeRaw(rawTx, d.cfg)
+}
+
+// putRawBuf returns buf to the pool of hex decoding buffers unless it grew
+// beyond maxPooledRawTx.
+func (d *Decoder) putRawBuf(buf *[]byte) {
+	if cap(*buf) > maxPooledRawTx {
+		return
+	}
+	*buf = (*buf)[:0]
+	d.rawBufs.Put(buf)
+}
+
+// DecodeBytes decodes raw transaction from raw payload.
+func (d *Decoder) DecodeBytes(rawTx []byte) (TxRawDecodeResult, error) {
+	return decodeRaw(rawTx, d.cfg)
+}
+
+// DecodeWire decodes wire msg.
+func (d *Decoder) DecodeWire(mtx *wire.MsgTx) (TxRawDecodeResult, error) {
+	return decodeMsgTx(mtx, d.cfg)
+}
@@ -0,0 +1,58 @@
+package rawdecodebtc
+
+import "testing"
+
+// TestDecoderReuse checks that results of a Decoder are left intact by the
+// decodes that follow, which reuse its pooled buffers.
+func TestDecoderReuse(t *testing.T) {
+	d, err := NewDecoder(WithNetwork("testnet"), WithRawScripts(true))
+	if err != nil {
+		t.Fatalf("NewDecoder: %v", err)
+	}
+
+	inputs := []string{segwitTxHex, legacyTxHex, segwitTxHex, legacyTxHex}
+	results := make([]TxRawDecodeResult, len(inputs))
+	for i, message := range inputs {
+		results[i], err = d.DecodeHex(message)
+		if err != nil {
+			t.Fatalf("DecodeHex %d: %v", i, err)
+		}
+	}
+
+	for i, txReply := range results {
+		got, err := txReply.ToHex()
+		if err != nil {
+			t.Fatalf("ToHex %d: %v", i, err)
+		}
+		if got != inputs[i] {
+			t.Errorf("result %d: transaction changed by later decodes", i)
+		}
+	}
+}
+
+// BenchmarkDecoderDecodeHex measures the allocations of DecodeHex, whose
+// pooled buffers save the one holding the decoded bytes.
+func BenchmarkDecoderDecodeHex(b *testing.B) {
+	d, err := NewDecoder(WithNetwork("testnet"))
+	if err != nil {
+		b.Fatalf("NewDecoder: %v", err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.DecodeHex(segwitTxHex); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFromHex is the baseline for BenchmarkDecoderDecodeHex, decoding
+// the same transaction without pooling.
+func BenchmarkFromHex(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromHex(segwitTxHex, "testnet"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package rawdecodebtc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Equal reports whether r and other describe the same transaction, see Diff.
+func (r TxRawDecodeResult) Equal(other TxRawDecodeResult) bool {
+	return len(r.Diff(other)) == 0
+}
+
+// Diff returns the fields that differ between r and other, such as "txid",
+// "vin[0].witness" or "vout[1].scriptPubKey". Only the serialized content of
+// the transaction is compared: derived fields like sizes, fees or anomalies
+// follow from it and are left out. Fields are reported in transaction order,
+// so a re-signed transaction that only changed witnesses yields "wtxid" and
+// the affected "vin[i].witness" entries.
+func (r TxRawDecodeResult) Diff(other TxRawDecodeResult) []string {
+	var diffs []string
+	add := func(differs bool, format string, args ...interface{}) {
+		if differs {
+			diffs = append(diffs, fmt.Sprintf(format, args...))
+		}
+	}
+
+	add(r.Txid != other.Txid, "txid")
+	add(r.Wtxid != other.Wtxid, "wtxid")
+	add(r.Version != other.Version, "version")
+	add(r.Locktime != other.Locktime, "locktime")
+
+	add(len(r.Vin) != len(other.Vin), "vin")
+	for i := 0; i < len(r.Vin) && i < len(other.Vin); i++ {
+		a, b := r.Vin[i], other.Vin[i]
+		add(a.Coinbase != b.Coinbase, "vin[%d].coinbase", i)
+		add(a.Txid != b.Txid || a.Vout != b.Vout, "vin[%d].prevout", i)
+		add(scriptSigHex(a) != scriptSigHex(b), "vin[%d].scriptSig", i)
+		add(strings.Join(a.Witness, " ") != strings.Join(b.Witness, " "),
+			"vin[%d].witness", i)
+		add(a.Sequence != b.Sequence, "vin[%d].sequence", i)
+	}
+
+	add(len(r.Vout) != len(other.Vout), "vout")
+	for i := 0; i < len(r.Vout) && i < len(other.Vout); i++ {
+		a, b := r.Vout[i], other.Vout[i]
+		add(a.N != b.N, "vout[%d].n", i)
+		add(a.ValueSat != b.ValueSat, "vout[%d].value", i)
+		add(a.ScriptPubKey.Hex != b.ScriptPubKey.Hex,
+			"vout[%d].scriptPubKey", i)
+	}
+
+	return diffs
+}
+
+// scriptSigHex returns the hex scriptSig of v, empty for coinbase inputs.
+func scriptSigHex(v Vin) string {
+	if v.ScriptSig == nil {
+		return ""
+	}
+	return v.ScriptSig.Hex
+}
@@ -0,0 +1,119 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Commitment prefixes of the confidential fields of an Elements output. A
+// prefix of 0x01 marks an explicit value and 0x00 an absent nonce.
+const (
+	elementsExplicit      = 0x01
+	elementsAssetCommit   = 0x0a
+	elementsAssetCommitN  = 0x0b
+	elementsValueCommit   = 0x08
+	elementsValueCommitN  = 0x09
+	elementsNonceNull     = 0x00
+	elementsNonceCommit   = 0x02
+	elementsNonceCommitN  = 0x03
+	elementsIssuanceFlag  = 1 << 31
+	elementsCommitmentLen = 33
+)
+
+// looksLikeElements reports whether rawTx opens like a transaction of an
+// Elements chain such as Liquid: a version, a flag byte, inputs as in
+// Bitcoin and a first output starting with the asset, value and nonce
+// commitments Bitcoin outputs lack. Inputs carrying an asset issuance are
+// enough of a sign on their own. It is only a hint for inputs failing to
+// decode as Bitcoin transactions.
+func looksLikeElements(rawTx []byte) bool {
+	r := bytes.NewReader(rawTx)
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil || header[4] > 1 {
+		return false
+	}
+
+	numIn, err := wire.ReadVarInt(r, 0)
+	if err != nil || numIn == 0 || numIn > uint64(r.Len()) {
+		return false
+	}
+	for i := uint64(0); i < numIn; i++ {
+		var outPoint [36]byte
+		if _, err := io.ReadFull(r, outPoint[:]); err != nil {
+			return false
+		}
+		index := uint32(outPoint[32]) | uint32(outPoint[33])<<8 |
+			uint32(outPoint[34])<<16 | uint32(outPoint[35])<<24
+		if index != wire.MaxPrevOutIndex && index&elementsIssuanceFlag != 0 {
+			return true
+		}
+		if !skipVarBytes(r) || !skip(r, 4) {
+			return false
+		}
+	}
+
+	numOut, err := wire.ReadVarInt(r, 0)
+	if err != nil || numOut == 0 {
+		return false
+	}
+	asset, err := r.ReadByte()
+	if err != nil {
+		return false
+	}
+	switch asset {
+	case elementsExplicit, elementsAssetCommit, elementsAssetCommitN:
+	default:
+		return false
+	}
+	if !skip(r, elementsCommitmentLen-1) {
+		return false
+	}
+
+	value, err := r.ReadByte()
+	if err != nil {
+		return false
+	}
+	switch value {
+	case elementsExplicit:
+		if !skip(r, 8) {
+			return false
+		}
+	case elementsValueCommit, elementsValueCommitN:
+		if !skip(r, elementsCommitmentLen-1) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	nonce, err := r.ReadByte()
+	if err != nil {
+		return false
+	}
+	switch nonce {
+	case elementsNonceNull:
+		return true
+	case elementsExplicit, elementsNonceCommit, elementsNonceCommitN:
+		return skip(r, elementsCommitmentLen-1)
+	default:
+		return false
+	}
+}
+
+// skip discards n bytes of r, reporting whether they were available.
+func skip(r *bytes.Reader, n int64) bool {
+	if int64(r.Len()) < n {
+		return false
+	}
+	_, err := r.Seek(n, io.SeekCurrent)
+	return err == nil
+}
+
+// skipVarBytes discards a CompactSize length prefixed field of r, reporting
+// whether it was complete.
+func skipVarBytes(r *bytes.Reader) bool {
+	n, err := wire.ReadVarInt(r, 0)
+	return err == nil && n <= uint64(r.Len()) && skip(r, int64(n))
+}
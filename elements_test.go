@@ -0,0 +1,36 @@
+package rawdecodebtc
+
+import (
+	"errors"
+	"testing"
+)
+
+// liquidTxHex is an unblinded transaction in the Liquid serialization,
+// spending one input to a P2WPKH output and the explicit fee output, both
+// paying the L-BTC asset with explicit values and null nonces.
+const liquidTxHex = "0200000000019e1f0a6ba5a4d1c47bf6c0ba5e3d20b6395e35985e889a2f4a5405d4e1b2c3d40000000000fdffffff02016d521c38ec1ea15734ae22b7c46064412829c0d0579f0a713d1c04ede979026f010000000005f5b9f000160014751e76e8199196d454941c45d1b3a323f1433bd6016d521c38ec1ea15734ae22b7c46064412829c0d0579f0a713d1c04ede979026f010000000000002710000000000000"
+
+// TestElementsTransaction checks that a Liquid transaction fails with
+// ErrUnsupportedFormat, while a truncated Bitcoin transaction keeps its
+// deserialization error.
+func TestElementsTransaction(t *testing.T) {
+	tests := []struct {
+		name            string
+		message         string
+		wantUnsupported bool
+	}{
+		{name: "liquid", message: liquidTxHex, wantUnsupported: true},
+		{name: "truncated bitcoin", message: legacyTxHex[:len(legacyTxHex)-30]},
+	}
+
+	for _, test := range tests {
+		_, err := FromHex(test.message, "mainnet")
+		if err == nil {
+			t.Fatalf("%s: decoded without error", test.name)
+		}
+		if got := errors.Is(err, ErrUnsupportedFormat); got != test.wantUnsupported {
+			t.Errorf("%s: error %v, want ErrUnsupportedFormat %v", test.name,
+				err, test.wantUnsupported)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package rawdecodebtc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidHex is returned when the input is not valid hex.
+var ErrInvalidHex = errors.New("invalid hex")
+
+// ErrInvalidBase64 is returned when the input is not valid base64.
+var ErrInvalidBase64 = errors.New("invalid base64")
+
+// ErrInvalidPSBT is returned when a PSBT is malformed or lacks a record
+// required to rebuild its transaction.
+var ErrInvalidPSBT = errors.New("invalid psbt")
+
+// ErrInvalidAddress is returned by AddressFilter when an entry is neither an
+// address of the chosen network nor a script hash.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ErrNilParams is returned when decoding with nil chain parameters.
+var ErrNilParams = errors.New("nil chain parameters")
+
+// ErrNilTx is returned when decoding a nil wire.MsgTx.
+var ErrNilTx = errors.New("nil transaction")
+
+// ErrTooManyInputs is returned when a transaction has more inputs than
+// allowed by WithMaxInputs.
+var ErrTooManyInputs = errors.New("too many inputs")
+
+// ErrTooManyOutputs is returned when a transaction has more outputs than
+// allowed by WithMaxOutputs.
+var ErrTooManyOutputs = errors.New("too many outputs")
+
+// ErrValueOutOfRange is returned by WithMoneyRange decoding when an output
+// value or their total lies outside the valid money range.
+var ErrValueOutOfRange = errors.New("value out of range")
+
+// ErrTxTooLarge is returned when a transaction read from a stream exceeds
+// the allowed size.
+var ErrTxTooLarge = errors.New("transaction exceeds maximum size")
+
+// ErrTrailingBytes is returned when bytes are left over after the raw
+// transaction, unless decoding with WithTrailingBytes.
+var ErrTrailingBytes = errors.New("trailing bytes after transaction")
+
+// ErrUnsupportedFormat is returned when the input is recognized as a
+// transaction of another chain format, such as Elements, rather than a
+// malformed Bitcoin transaction.
+var ErrUnsupportedFormat = errors.New("unsupported transaction format")
+
+// ErrSizeMismatch is returned by VerifySizes when a transaction does not
+// serialize back to the size it was read from.
+var ErrSizeMismatch = errors.New("serialized size mismatch")
+
+// ErrLengthMismatch is returned by FromHexPrefixed when the length prefix
+// does not match the transaction that follows.
+var ErrLengthMismatch = errors.New("length prefix mismatch")
+
+// ErrDecodePanic is returned by SafeFromHex when decoding panicked.
+var ErrDecodePanic = errors.New("panic while decoding")
+
+// ErrNoTxHex is returned by FromRPCResult when the input is neither a hex
+// string nor a JSON object with a hex field.
+var ErrNoTxHex = errors.New("no transaction hex found")
+
+// DecodeError describes a failure to deserialize raw bytes into a
+// transaction or block.
+type DecodeError struct {
+	// Stage names what was being deserialized, "transaction", "block" or
+	// "length prefix".
+	Stage string
+
+	// Offset is the number of bytes consumed before the failure, which
+	// hints at where the input is truncated or malformed.
+	Offset int64
+
+	// Err is the underlying deserialization error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode %s at byte %d: %v", e.Stage, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying deserialization error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// countingReader counts the bytes read through it so that decode errors can
+// report an offset.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,79 @@
+package rawdecodebtc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// ErrNegativeFee is returned when the outputs spent by a transaction are
+// worth less than the outputs it pays, so that it cannot be valid.
+var ErrNegativeFee = errors.New("inputs worth less than outputs")
+
+// FromHexWithPrevouts decodes raw transaction from Hex payload and computes
+// its fee from prevouts, which maps every spent outpoint to its value in
+// satoshi. TotalIn, Fee (BTC) and FeeRate (sat/vB) are populated on the
+// result, Fee staying nil otherwise so that a zero fee is told from an unknown
+// one. Coinbase transactions spend no previous outputs and are returned
+// without fee information. Decoding fails with ErrNegativeFee when the
+// prevouts are worth less than the outputs.
+func FromHexWithPrevouts(message string, net string, prevouts map[wire.OutPoint]int64) (txReply TxRawDecodeResult, err error) {
+	return Decode([]byte(message), WithNetwork(net), WithHexInput(),
+		WithPrevouts(prevouts))
+}
+
+// setFee fills the input total and fee fields of txReply from the values of
+// the outputs spent by mtx, expressing amounts in unit. It fails with
+// ErrNegativeFee when the inputs spend less than the outputs pay.
+func setFee(txReply *TxRawDecodeResult, mtx *wire.MsgTx, prevouts map[wire.OutPoint]int64, unit btcutil.AmountUnit) error {
+	if blockchain.IsCoinBaseTx(mtx) {
+		return nil
+	}
+
+	var totalIn int64
+	for _, txIn := range mtx.TxIn {
+		value, ok := prevouts[txIn.PreviousOutPoint]
+		if !ok {
+			return fmt.Errorf("missing prevout value for input %v",
+				txIn.PreviousOutPoint)
+		}
+		totalIn += value
+	}
+
+	fee := totalIn - int64(totalOut(mtx))
+	if fee < 0 {
+		return fmt.Errorf("%w: inputs total %d sat, outputs %d sat",
+			ErrNegativeFee, totalIn, int64(totalOut(mtx)))
+	}
+	feeAmount := btcutil.Amount(fee).ToUnit(unit)
+	txReply.TotalIn = btcutil.Amount(totalIn).ToUnit(unit)
+	txReply.Fee = &feeAmount
+	txReply.FeeRate = float64(fee) / float64(txReply.Vsize)
+	return nil
+}
+
+// virtualSize returns the BIP141 virtual size of mtx, its weight divided by
+// four and rounded up.
+func virtualSize(mtx *wire.MsgTx) int64 {
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(mtx))
+	return (weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+}
+
+// PrevOutpoints returns the outpoints spent by mtx, one per input in input
+// order, ready to be looked up before calling FromHexWithPrevouts. Coinbase
+// transactions spend nothing and yield an empty slice.
+func PrevOutpoints(mtx *wire.MsgTx) []wire.OutPoint {
+	if blockchain.IsCoinBaseTx(mtx) {
+		return []wire.OutPoint{}
+	}
+
+	outpoints := make([]wire.OutPoint, len(mtx.TxIn))
+	for i, txIn := range mtx.TxIn {
+		outpoints[i] = txIn.PreviousOutPoint
+	}
+	return outpoints
+}
@@ -0,0 +1,63 @@
+package rawdecodebtc
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestFromHexWithPrevouts checks the fee computed from the spent output
+// values, including a zero fee, which must still be reported, and inputs
+// worth less than the outputs, which must be rejected.
+func TestFromHexWithPrevouts(t *testing.T) {
+	hash, err := chainhash.NewHashFromStr(
+		"ae7054e8e1cc529661b14730647d9f89b89093c34b01d85721ae7094438b5f96")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wire.OutPoint{Hash: *hash, Index: 0}
+	const totalOut = 3847171
+
+	tests := []struct {
+		name    string
+		value   int64
+		fee     float64
+		wantErr error
+	}{
+		{name: "fee", value: totalOut + 10000, fee: 0.0001},
+		{name: "zero fee", value: totalOut, fee: 0},
+		{name: "negative fee", value: totalOut - 1, wantErr: ErrNegativeFee},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prevouts := map[wire.OutPoint]int64{op: test.value}
+			txReply, err := FromHexWithPrevouts(legacyTxHex, "testnet",
+				prevouts)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("err = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if txReply.Fee == nil || *txReply.Fee != test.fee {
+				t.Fatalf("fee = %v, want %v", txReply.Fee, test.fee)
+			}
+			b, err := json.Marshal(txReply)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), `"fee":`) {
+				t.Errorf("JSON lacks the fee: %s", b)
+			}
+		})
+	}
+}
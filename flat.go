@@ -0,0 +1,102 @@
+package rawdecodebtc
+
+// FlatTx is a flattened view of a decoded transaction for use in templates,
+// where the nested ScriptSig and ScriptPubKey structures are awkward to
+// reach. Nested fields are hoisted onto their parent with the name of the
+// structure as prefix, so ScriptPubKey.Hex becomes ScriptPubKeyHex.
+type FlatTx struct {
+	Txid         string
+	Wtxid        string
+	Version      int32
+	Locktime     uint32
+	LocktimeType string
+	Size         int
+	Vsize        int
+	Weight       int64
+	IsCoinbase   bool
+	TotalOut     float64
+	Fee          *float64
+	Vin          []FlatVin
+	Vout         []FlatVout
+}
+
+// FlatVin is the flattened view of a Vin.
+type FlatVin struct {
+	Coinbase     string
+	Txid         string
+	Vout         uint32
+	Outpoint     string
+	ScriptSigAsm string
+	ScriptSigHex string
+	Witness      []string
+	Sequence     uint32
+	InputType    string
+}
+
+// FlatVout is the flattened view of a Vout.
+type FlatVout struct {
+	N                     uint32
+	Value                 float64
+	ValueSat              int64
+	ScriptPubKeyAsm       string
+	ScriptPubKeyHex       string
+	ScriptPubKeyType      string
+	ScriptPubKeyReqSigs   int32
+	ScriptPubKeyAddresses []string
+	AddressType           string
+	IsDust                bool
+}
+
+// FlatView returns the flattened view of the decoded transaction. The result
+// itself is left untouched for JSON use.
+func (r TxRawDecodeResult) FlatView() FlatTx {
+	flat := FlatTx{
+		Txid:         r.Txid,
+		Wtxid:        r.Wtxid,
+		Version:      r.Version,
+		Locktime:     r.Locktime,
+		LocktimeType: r.LocktimeType,
+		Size:         r.SerializeSize,
+		Vsize:        r.Vsize,
+		Weight:       r.Weight,
+		IsCoinbase:   r.IsCoinbase,
+		TotalOut:     r.TotalOut,
+		Fee:          r.Fee,
+		Vin:          make([]FlatVin, len(r.Vin)),
+		Vout:         make([]FlatVout, len(r.Vout)),
+	}
+
+	for i, vin := range r.Vin {
+		flatVin := FlatVin{
+			Coinbase:  vin.Coinbase,
+			Txid:      vin.Txid,
+			Vout:      vin.Vout,
+			Outpoint:  vin.Outpoint,
+			Witness:   vin.Witness,
+			Sequence:  vin.Sequence,
+			InputType: vin.InputType,
+		}
+		if vin.ScriptSig != nil {
+			flatVin.ScriptSigAsm = vin.ScriptSig.Asm
+			flatVin.ScriptSigHex = vin.ScriptSig.Hex
+		}
+		flat.Vin[i] = flatVin
+	}
+
+	for i, vout := range r.Vout {
+		flat.Vout[i] = FlatVout{
+			N:                     vout.N,
+			Value:                 vout.Value,
+			ValueSat:              vout.ValueSat,
+			ScriptPubKeyAsm:       vout.ScriptPubKey.Asm,
+			ScriptPubKeyHex:       vout.ScriptPubKey.Hex,
+			ScriptPubKeyType:      vout.ScriptPubKey.Type,
+			ScriptPubKeyReqSigs:   vout.ScriptPubKey.ReqSigs,
+			ScriptPubKeyAddresses: vout.ScriptPubKey.Addresses,
+			AddressType:           vout.AddressType,
+			IsDust:                vout.IsDust,
+		}
+	}
+
+	return flat
+}
@@ -0,0 +1,113 @@
+package rawdecodebtc
+
+import (
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Input types reported in Vin.InputType.
+const (
+	InputCoinbase       = "coinbase"
+	InputP2PK           = "P2PK"
+	InputP2PKH          = "P2PKH"
+	InputP2SH           = "P2SH"
+	InputP2SHP2WPKH     = "P2SH-P2WPKH"
+	InputP2SHP2WSH      = "P2SH-P2WSH"
+	InputP2WPKH         = "P2WPKH"
+	InputP2WSH          = "P2WSH"
+	InputP2TRKeyPath    = "P2TR-keypath"
+	InputP2TRScriptPath = "P2TR-scriptpath"
+	InputUnknown        = "unknown"
+)
+
+// isTaprootInput reports whether inputType is one of the P2TR input types.
+func isTaprootInput(inputType string) bool {
+	return inputType == InputP2TRKeyPath || inputType == InputP2TRScriptPath
+}
+
+// classifyInput infers the kind of output spent by txIn from the shape of its
+// scriptSig and witness, since the spent output itself is not part of the
+// transaction:
+//
+//   - P2PK: a scriptSig with a single signature and no witness.
+//   - P2PKH: a scriptSig with a signature and a public key and no witness.
+//   - P2SH: a push only scriptSig ending with a redeem script.
+//   - P2SH-P2WPKH and P2SH-P2WSH: a scriptSig pushing only a version 0
+//     witness program of 20 or 32 bytes as redeem script, and a witness.
+//   - P2WPKH: an empty scriptSig and a witness of a signature and a
+//     compressed public key.
+//   - P2TR-keypath and P2TR-scriptpath: an empty scriptSig and a witness
+//     accepted by ParseTaprootWitness.
+//   - P2WSH: an empty scriptSig and a witness ending with a witness script.
+//
+// Anything else is reported as unknown.
+func classifyInput(txIn *wire.TxIn, coinbase bool) string {
+	if coinbase {
+		return InputCoinbase
+	}
+
+	sigScript, witness := txIn.SignatureScript, txIn.Witness
+	if len(sigScript) == 0 {
+		return classifyWitness(witness)
+	}
+
+	if len(witness) == 0 && txscript.IsPushOnlyScript(sigScript) {
+		pushes, err := txscript.PushedData(sigScript)
+		if err == nil {
+			switch {
+			case len(pushes) == 1 && isSignatureLike(pushes[0]):
+				return InputP2PK
+			case len(pushes) == 2 && isSignatureLike(pushes[0]) &&
+				isPubKeyLike(pushes[1]):
+				return InputP2PKH
+			}
+		}
+	}
+
+	if script, ok := RedeemScript(sigScript); ok {
+		if len(witness) > 0 {
+			if nested := classifyNestedWitness(sigScript, script); nested != "" {
+				return nested
+			}
+		}
+		return InputP2SH
+	}
+	return InputUnknown
+}
+
+// classifyNestedWitness returns the input type of a P2SH spend revealing
+// redeemScript as the only push of sigScript, when redeemScript is a version
+// 0 witness program, or an empty string otherwise.
+func classifyNestedWitness(sigScript, redeemScript []byte) string {
+	pushes, err := txscript.PushedData(sigScript)
+	if err != nil || len(pushes) != 1 {
+		return ""
+	}
+
+	switch txscript.GetScriptClass(redeemScript) {
+	case txscript.WitnessV0PubKeyHashTy:
+		return InputP2SHP2WPKH
+	case txscript.WitnessV0ScriptHashTy:
+		return InputP2SHP2WSH
+	default:
+		return ""
+	}
+}
+
+// classifyWitness infers the kind of native segwit output spent by an input
+// with an empty scriptSig.
+func classifyWitness(witness wire.TxWitness) string {
+	if len(witness) == 2 && len(witness[1]) == 33 && isPubKeyLike(witness[1]) {
+		return InputP2WPKH
+	}
+	if info, ok := ParseTaprootWitness(witness); ok {
+		if info.KeyPath {
+			return InputP2TRKeyPath
+		}
+		return InputP2TRScriptPath
+	}
+	if _, ok := WitnessScript(witness); ok {
+		return InputP2WSH
+	}
+	return InputUnknown
+}
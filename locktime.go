@@ -0,0 +1,119 @@
+package rawdecodebtc
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// Locktime types reported in TxRawDecodeResult.LocktimeType.
+const (
+	LocktimeNone   = "none"
+	LocktimeHeight = "height"
+	LocktimeTime   = "time"
+)
+
+// locktimeInfo interprets the locktime of mtx. Values below
+// txscript.LockTimeThreshold are block heights and the rest are Unix
+// timestamps. The locktime is disabled when it is zero or every input has a
+// final sequence number, in which case LocktimeNone is returned.
+func locktimeInfo(mtx *wire.MsgTx) (string, *time.Time) {
+	if mtx.LockTime == 0 || !locktimeEnabled(mtx) {
+		return LocktimeNone, nil
+	}
+
+	if mtx.LockTime < txscript.LockTimeThreshold {
+		return LocktimeHeight, nil
+	}
+
+	t := time.Unix(int64(mtx.LockTime), 0).UTC()
+	return LocktimeTime, &t
+}
+
+// locktimeEnabled reports whether at least one input of mtx has a non-final
+// sequence number, which is required for the locktime to be enforced.
+func locktimeEnabled(mtx *wire.MsgTx) bool {
+	for _, txIn := range mtx.TxIn {
+		if txIn.Sequence != wire.MaxTxInSequenceNum {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether mtx may be included in a block at blockHeight with
+// timestamp blockTime, as far as its locktime goes, as consensus checks with
+// blockchain.IsFinalizedTransaction. To test admission into the next block,
+// pass the height and median time past of that block.
+func IsFinal(mtx *wire.MsgTx, blockHeight int32, blockTime time.Time) bool {
+	return blockchain.IsFinalizedTransaction(btcutil.NewTx(mtx), blockHeight,
+		blockTime)
+}
+
+// Relative locktime types reported in SequenceInfo.Type.
+const (
+	RelativeLockBlocks = "blocks"
+	RelativeLockTime   = "time"
+)
+
+// SequenceInfo is the BIP68 relative locktime encoded in an input sequence.
+type SequenceInfo struct {
+	// Enabled is false when the sequence disables the relative locktime,
+	// in which case the other fields are zero.
+	Enabled bool `json:"enabled"`
+
+	// Type is RelativeLockBlocks or RelativeLockTime.
+	Type string `json:"type,omitempty"`
+
+	// Value is the lock in blocks, or in units of 512 seconds for time
+	// based locks.
+	Value uint32 `json:"value,omitempty"`
+
+	// Seconds is the duration of a time based lock.
+	Seconds uint32 `json:"seconds,omitempty"`
+}
+
+// ParseSequence decodes the BIP68 relative locktime of an input sequence.
+// Only the sequence is looked at, while the lock only applies to transactions
+// for which RelativeLockEnforced holds.
+func ParseSequence(seq uint32) SequenceInfo {
+	if seq&wire.SequenceLockTimeDisabled != 0 {
+		return SequenceInfo{}
+	}
+
+	value := seq & wire.SequenceLockTimeMask
+	if seq&wire.SequenceLockTimeIsSeconds != 0 {
+		return SequenceInfo{
+			Enabled: true,
+			Type:    RelativeLockTime,
+			Value:   value,
+			Seconds: value << wire.SequenceLockTimeGranularity,
+		}
+	}
+	return SequenceInfo{Enabled: true, Type: RelativeLockBlocks, Value: value}
+}
+
+// relativeLockVersion is the lowest transaction version enforcing BIP68.
+const relativeLockVersion = 2
+
+// RelativeLockEnforced reports whether transactions of the given version have
+// the relative locktimes of their input sequences enforced by consensus, as
+// per BIP68. Like Bitcoin Core, the version is compared as an unsigned number,
+// so negative versions enforce them too. Sequences of earlier versions carry
+// no relative lock whatever their bits, see ParseSequence.
+func RelativeLockEnforced(version int32) bool {
+	return uint32(version) >= relativeLockVersion
+}
+
+// relativeLock returns the relative locktime of an input sequence, or nil
+// when it is disabled.
+func relativeLock(seq uint32) *SequenceInfo {
+	info := ParseSequence(seq)
+	if !info.Enabled {
+		return nil
+	}
+	return &info
+}
@@ -0,0 +1,32 @@
+package rawdecodebtc
+
+import "time"
+
+// Decode stages reported to the hook set by WithMetrics.
+const (
+	// StageDeserialize covers reading the raw transaction bytes.
+	StageDeserialize = "deserialize"
+
+	// StageVin covers building Vin.
+	StageVin = "vin"
+
+	// StageVout covers building Vout, address encoding included.
+	StageVout = "vout"
+)
+
+// startStage returns the start time of a decode stage, or the zero time when
+// no metrics hook is set, sparing the clock read.
+func (cfg *decodeConfig) startStage() time.Time {
+	if cfg.metrics == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// endStage reports the time spent in stage since start to the metrics hook,
+// if any.
+func (cfg *decodeConfig) endStage(stage string, start time.Time) {
+	if cfg.metrics != nil {
+		cfg.metrics(stage, time.Since(start))
+	}
+}
@@ -0,0 +1,268 @@
+package rawdecodebtc
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// Option configures the behavior of Decode.
+type Option func(*decodeConfig)
+
+// decodeConfig holds the settings a transaction is decoded with.
+type decodeConfig struct {
+	params         *chaincfg.Params
+	filterAddrMap  map[string]struct{}
+	prevouts       map[wire.OutPoint]int64
+	hexInput       bool
+	noWitness      bool
+	rawScripts     bool
+	maxInputs      int
+	maxOutputs     int
+	moneyRange     bool
+	noAddresses    bool
+	amountUnit     btcutil.AmountUnit
+	allowTrailing  bool
+	utxoProvider   UTXOProvider
+	metrics        func(stage string, d time.Duration)
+	addressEncoder func(addr btcutil.Address) string
+	partial        bool
+	maxDataCarrier int
+	altAddresses   bool
+	maxTxSize      int
+
+	// err records an invalid option, reported by newDecodeConfig.
+	err error
+}
+
+// newDecodeConfig applies opts over the mainnet defaults.
+func newDecodeConfig(opts []Option) (*decodeConfig, error) {
+	cfg := &decodeConfig{params: mainnet}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+	return cfg, nil
+}
+
+// WithNetwork selects the chain parameters by network name, one of
+// "mainnet", "testnet", "regtest" or "signet". Decoding fails with
+// ErrUnknownNetwork for any other name.
+func WithNetwork(net string) Option {
+	return func(cfg *decodeConfig) {
+		cfg.params, cfg.err = paramsForNet(net)
+	}
+}
+
+// WithParams selects caller supplied chain parameters, for chains not covered
+// by the network names. Decoding fails with ErrNilParams when params is nil.
+func WithParams(params *chaincfg.Params) Option {
+	return func(cfg *decodeConfig) {
+		if params == nil {
+			cfg.err = ErrNilParams
+			return
+		}
+		cfg.params = params
+	}
+}
+
+// WithFilterAddrs keeps only the outputs paying one of addrs. Filtered out
+// outputs keep their original index in Vout. An empty or nil filter keeps all
+// outputs. Besides addresses, addrs may hold the script hash of P2SH and P2WSH
+// outputs in lowercase hex, the HASH160 or SHA256 of the redeem or witness
+// script, to watch for a script without knowing its address.
+func WithFilterAddrs(addrs []string) Option {
+	return func(cfg *decodeConfig) {
+		cfg.filterAddrMap = filterMap(addrs)
+	}
+}
+
+// WithPrevouts supplies the value in satoshi of every outpoint spent by the
+// transaction, used to compute its fee as by FromHexWithPrevouts.
+func WithPrevouts(prevouts map[wire.OutPoint]int64) Option {
+	return func(cfg *decodeConfig) {
+		cfg.prevouts = prevouts
+	}
+}
+
+// WithHexInput makes Decode treat its input as hex text, normalized as by
+// HexDecodeRawTxString, rather than raw bytes.
+func WithHexInput() Option {
+	return func(cfg *decodeConfig) {
+		cfg.hexInput = true
+	}
+}
+
+// withNoWitness makes Decode read the legacy serialization format.
+func withNoWitness() Option {
+	return func(cfg *decodeConfig) {
+		cfg.noWitness = true
+	}
+}
+
+// WithMaxInputs makes decoding fail with ErrTooManyInputs for transactions
+// with more than n inputs, before the per-input work is done. Zero, the
+// default, means no limit.
+func WithMaxInputs(n int) Option {
+	return func(cfg *decodeConfig) {
+		cfg.maxInputs = n
+	}
+}
+
+// WithMaxOutputs makes decoding fail with ErrTooManyOutputs for transactions
+// with more than n outputs, before the per-output work is done. Zero, the
+// default, means no limit.
+func WithMaxOutputs(n int) Option {
+	return func(cfg *decodeConfig) {
+		cfg.maxOutputs = n
+	}
+}
+
+// WithRawScripts sets whether the raw scriptSig and pkScript bytes are kept
+// on each Vin and Vout, sparing callers doing further script analysis a hex
+// round trip. It is off by default.
+func WithRawScripts(raw bool) Option {
+	return func(cfg *decodeConfig) {
+		cfg.rawScripts = raw
+	}
+}
+
+// WithMoneyRange makes decoding fail with ErrValueOutOfRange when an output
+// value is negative or above btcutil.MaxSatoshi, or when the output values
+// sum above it. Without it such transactions decode, flagged with
+// AnomalyValueOutOfRange.
+func WithMoneyRange() Option {
+	return func(cfg *decodeConfig) {
+		cfg.moneyRange = true
+	}
+}
+
+// WithoutAddresses skips extracting and encoding the addresses paid by each
+// output, which dominates decoding time for transactions with many outputs.
+// ScriptPubKey.Addresses is then empty and ScriptPubKey.ReqSigs zero, while
+// the script type is still reported. Along with an address filter the
+// addresses are still extracted to match outputs against it, and only left
+// out of the result.
+func WithoutAddresses() Option {
+	return func(cfg *decodeConfig) {
+		cfg.noAddresses = true
+	}
+}
+
+// WithAmountUnit expresses the Value of outputs and the TotalOut, TotalIn and
+// Fee amounts in unit, such as btcutil.AmountMilliBTC, btcutil.AmountMicroBTC
+// for bits or btcutil.AmountSatoshi. The default is btcutil.AmountBTC.
+// ValueSat and FeeRate are always in satoshi.
+func WithAmountUnit(unit btcutil.AmountUnit) Option {
+	return func(cfg *decodeConfig) {
+		cfg.amountUnit = unit
+	}
+}
+
+// WithTrailingBytes lets decoding succeed when bytes are left over after the
+// raw transaction, which are then ignored. By default they fail with an error
+// wrapping ErrTrailingBytes, as they usually reveal a concatenation bug or
+// corrupt input. To read concatenated transactions use DecodeAll instead.
+func WithTrailingBytes() Option {
+	return func(cfg *decodeConfig) {
+		cfg.allowTrailing = true
+	}
+}
+
+// WithUTXOProvider looks up the outputs spent by the transaction with
+// provider to set the Value and PrevScriptType of each input and the fee, as
+// WithPrevouts does, which it takes precedence over. Lookup is called once
+// per input, in input order, from the decoding goroutine, and never
+// concurrently by a single decode, though a provider given to a Decoder used
+// from several goroutines must be safe for concurrent use. Providers backed
+// by a remote store can batch by prefetching the outpoints returned by
+// PrevOutpoints. A failed lookup fails the decoding.
+func WithUTXOProvider(provider UTXOProvider) Option {
+	return func(cfg *decodeConfig) {
+		cfg.utxoProvider = provider
+	}
+}
+
+// WithMetrics calls hook with the time spent in each decode stage,
+// StageDeserialize, StageVin and StageVout, to find the hot stage of a
+// decode service. StageDeserialize is not reported when decoding an already
+// deserialized wire.MsgTx. Without it no time is measured.
+// The hook is called from the decoding goroutine and must be safe for
+// concurrent use when decodes run in parallel.
+func WithMetrics(hook func(stage string, d time.Duration)) Option {
+	return func(cfg *decodeConfig) {
+		cfg.metrics = hook
+	}
+}
+
+// WithAddressEncoder turns the addresses paid by outputs into strings with
+// encode instead of their EncodeAddress method, for chains whose address
+// presentation chaincfg.Params cannot describe. The address filter matches
+// against the strings encode returns.
+func WithAddressEncoder(encode func(addr btcutil.Address) string) Option {
+	return func(cfg *decodeConfig) {
+		cfg.addressEncoder = encode
+	}
+}
+
+// WithPartialResult makes decoding of raw bytes failing to deserialize return
+// what could be read before the failure along with the error, to diagnose
+// truncated or malformed transactions: the version, the segwit marker, the
+// inputs and outputs read in full, the witnesses read so far and the locktime
+// when it was reached. Hashes, sizes and the other fields are left unset. It
+// has no effect on streams and wire.MsgTx input.
+func WithPartialResult() Option {
+	return func(cfg *decodeConfig) {
+		cfg.partial = true
+	}
+}
+
+// WithTrimMemo sets the largest amount of data, in bytes, an OP_RETURN output
+// may carry before being flagged with AnomalyOversizedOpReturn, to tell
+// policy standard data outputs from oversized ones. A size of zero or less
+// keeps the default, DefaultMaxDataCarrierSize.
+func WithTrimMemo(maxDataCarrierSize int) Option {
+	return func(cfg *decodeConfig) {
+		cfg.maxDataCarrier = maxDataCarrierSize
+	}
+}
+
+// WithAltAddresses sets the AltAddresses of each output to the other
+// addresses the same key or script is known by, to bridge systems keying
+// outputs on different address forms. See Vout for the forms covered. They
+// are encoded as set by WithAddressEncoder.
+func WithAltAddresses() Option {
+	return func(cfg *decodeConfig) {
+		cfg.altAddresses = true
+	}
+}
+
+// WithMaxTxSize sets the largest transaction, in bytes, read from a stream
+// by FromReaderContext before failing with ErrTxTooLarge, to bound the memory
+// spent on untrusted input. A size of zero or less keeps the default,
+// MaxTxSize.
+func WithMaxTxSize(n int) Option {
+	return func(cfg *decodeConfig) {
+		cfg.maxTxSize = n
+	}
+}
+
+// txSizeLimit returns the stream transaction size limit of cfg.
+func (cfg *decodeConfig) txSizeLimit() int {
+	if cfg.maxTxSize <= 0 {
+		return MaxTxSize
+	}
+	return cfg.maxTxSize
+}
+
+// dataCarrierLimit returns the OP_RETURN data size limit of cfg.
+func (cfg *decodeConfig) dataCarrierLimit() int {
+	if cfg.maxDataCarrier <= 0 {
+		return DefaultMaxDataCarrierSize
+	}
+	return cfg.maxDataCarrier
+}
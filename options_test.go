@@ -0,0 +1,92 @@
+package rawdecodebtc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestNilArguments checks that nil chain parameters and transactions fail
+// with an error instead of panicking.
+func TestNilArguments(t *testing.T) {
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	tests := []struct {
+		name    string
+		decode  func() (TxRawDecodeResult, error)
+		wantErr error
+	}{
+		{
+			name: "FromHexWithParams",
+			decode: func() (TxRawDecodeResult, error) {
+				return FromHexWithParams(legacyTxHex, nil)
+			},
+			wantErr: ErrNilParams,
+		},
+		{
+			name: "FromWireWithParams",
+			decode: func() (TxRawDecodeResult, error) {
+				return FromWireWithParams(mtx, nil)
+			},
+			wantErr: ErrNilParams,
+		},
+		{
+			name: "FromWire",
+			decode: func() (TxRawDecodeResult, error) {
+				return FromWire(nil, "mainnet")
+			},
+			wantErr: ErrNilTx,
+		},
+		{
+			name: "Decoder.DecodeWire",
+			decode: func() (TxRawDecodeResult, error) {
+				d, err := NewDecoder()
+				if err != nil {
+					return TxRawDecodeResult{}, err
+				}
+				return d.DecodeWire(nil)
+			},
+			wantErr: ErrNilTx,
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := test.decode(); !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.wantErr)
+		}
+	}
+}
+
+// TestWithoutAddressesFilter checks that WithoutAddresses still lets an
+// address filter match outputs, while leaving the addresses out of them.
+func TestWithoutAddressesFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  []string
+		wantOut int
+	}{
+		{name: "paid address", filter: []string{"mvpfGupqZWA39pujo6H6DjbziY2SZNDra2"}, wantOut: 1},
+		{name: "other address", filter: []string{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"}, wantOut: 0},
+	}
+
+	for _, test := range tests {
+		txReply, err := Decode([]byte(legacyTxHex), WithNetwork("testnet"),
+			WithHexInput(), WithFilterAddrs(test.filter), WithoutAddresses())
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", test.name, err)
+		}
+		if len(txReply.Vout) != test.wantOut {
+			t.Fatalf("%s: %d outputs, want %d", test.name, len(txReply.Vout),
+				test.wantOut)
+		}
+		for _, vout := range txReply.Vout {
+			if len(vout.ScriptPubKey.Addresses) != 0 ||
+				vout.ScriptPubKey.ReqSigs != 0 {
+				t.Errorf("%s: output %d reports addresses %v, reqSigs %d",
+					test.name, vout.N, vout.ScriptPubKey.Addresses,
+					vout.ScriptPubKey.ReqSigs)
+			}
+		}
+	}
+}
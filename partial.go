@@ -0,0 +1,121 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// partialResult builds what can be recovered from the raw transaction rawTx
+// that failed to deserialize: the version, the segwit marker, the inputs and
+// outputs read in full before the failure, the witnesses read so far and the
+// locktime when it was reached. Hashes, sizes and every other field are left
+// unset, as they would describe a transaction that does not exist.
+func partialResult(rawTx []byte, cfg *decodeConfig) TxRawDecodeResult {
+	mtx, marker, locktime := readPartialTx(bytes.NewReader(rawTx), cfg.noWitness)
+
+	txReply := TxRawDecodeResult{
+		Version:         mtx.Version,
+		HasWitness:      mtx.HasWitness(),
+		HasSegwitMarker: marker,
+		Vin:             createVinList(mtx, cfg),
+		Vout:            createVoutList(mtx, cfg),
+	}
+	if locktime {
+		txReply.Locktime = mtx.LockTime
+	}
+	return txReply
+}
+
+// readPartialTx reads a transaction from r field by field, as
+// wire.MsgTx.Deserialize does, stopping at the first field that cannot be
+// read in full. It returns what was read so far, whether the segwit marker
+// was found and whether the locktime was reached.
+func readPartialTx(r *bytes.Reader, noWitness bool) (*wire.MsgTx, bool, bool) {
+	mtx := &wire.MsgTx{}
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return mtx, false, false
+	}
+	mtx.Version = int32(binary.LittleEndian.Uint32(buf[:4]))
+
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return mtx, false, false
+	}
+	var marker bool
+	if count == 0 && !noWitness {
+		flag, err := r.ReadByte()
+		if err != nil || flag != 0x01 {
+			return mtx, false, false
+		}
+		marker = true
+		if count, err = wire.ReadVarInt(r, 0); err != nil {
+			return mtx, marker, false
+		}
+	}
+
+	for i := uint64(0); i < count; i++ {
+		var op [36]byte
+		if _, err := io.ReadFull(r, op[:]); err != nil {
+			return mtx, marker, false
+		}
+		sigScript, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload,
+			"signature script")
+		if err != nil {
+			return mtx, marker, false
+		}
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return mtx, marker, false
+		}
+
+		var hash chainhash.Hash
+		copy(hash[:], op[:chainhash.HashSize])
+		index := binary.LittleEndian.Uint32(op[chainhash.HashSize:])
+		txIn := wire.NewTxIn(wire.NewOutPoint(&hash, index), sigScript, nil)
+		txIn.Sequence = binary.LittleEndian.Uint32(buf[:4])
+		mtx.AddTxIn(txIn)
+	}
+
+	if count, err = wire.ReadVarInt(r, 0); err != nil {
+		return mtx, marker, false
+	}
+	for i := uint64(0); i < count; i++ {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return mtx, marker, false
+		}
+		pkScript, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload,
+			"public key script")
+		if err != nil {
+			return mtx, marker, false
+		}
+		value := int64(binary.LittleEndian.Uint64(buf[:]))
+		mtx.AddTxOut(wire.NewTxOut(value, pkScript))
+	}
+
+	if marker {
+		for _, txIn := range mtx.TxIn {
+			count, err := wire.ReadVarInt(r, 0)
+			if err != nil {
+				return mtx, marker, false
+			}
+			for j := uint64(0); j < count; j++ {
+				item, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload,
+					"script witness item")
+				if err != nil {
+					return mtx, marker, false
+				}
+				txIn.Witness = append(txIn.Witness, item)
+			}
+		}
+	}
+
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return mtx, marker, false
+	}
+	mtx.LockTime = binary.LittleEndian.Uint32(buf[:4])
+	return mtx, marker, true
+}
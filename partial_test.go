@@ -0,0 +1,72 @@
+package rawdecodebtc
+
+import "testing"
+
+// TestPartialResult checks what WithPartialResult recovers from transactions
+// cut short at different fields.
+func TestPartialResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantVersion int32
+		wantMarker  bool
+		wantVin     int
+		wantVout    []int64
+		wantWitness int
+	}{
+		{
+			// Cut inside the public key, the second witness item.
+			name:        "segwit within witness",
+			message:     segwitTxHex[:len(segwitTxHex)-40],
+			wantVersion: 2,
+			wantMarker:  true,
+			wantVin:     1,
+			wantVout:    []int64{4979993360, 10000000},
+			wantWitness: 1,
+		},
+		{
+			// Cut inside the script of the only output.
+			name:        "legacy within output",
+			message:     legacyTxHex[:len(legacyTxHex)-30],
+			wantVersion: 1,
+			wantVin:     1,
+		},
+	}
+
+	for _, test := range tests {
+		txReply, err := Decode([]byte(test.message), WithNetwork("testnet"),
+			WithHexInput(), WithPartialResult())
+		if err == nil {
+			t.Fatalf("%s: truncated transaction decoded without error", test.name)
+		}
+		if txReply.Txid != "" {
+			t.Errorf("%s: txid %s set on a partial result", test.name, txReply.Txid)
+		}
+		if txReply.Version != test.wantVersion {
+			t.Errorf("%s: version %d, want %d", test.name, txReply.Version,
+				test.wantVersion)
+		}
+		if txReply.HasSegwitMarker != test.wantMarker {
+			t.Errorf("%s: HasSegwitMarker %v, want %v", test.name,
+				txReply.HasSegwitMarker, test.wantMarker)
+		}
+		if len(txReply.Vin) != test.wantVin {
+			t.Fatalf("%s: %d inputs, want %d", test.name, len(txReply.Vin),
+				test.wantVin)
+		}
+		if len(txReply.Vout) != len(test.wantVout) {
+			t.Fatalf("%s: %d outputs, want %d", test.name, len(txReply.Vout),
+				len(test.wantVout))
+		}
+		for i, vout := range txReply.Vout {
+			if vout.ValueSat != test.wantVout[i] {
+				t.Errorf("%s: output %d worth %d sat, want %d", test.name, i,
+					vout.ValueSat, test.wantVout[i])
+			}
+		}
+		if test.wantVin > 0 && len(txReply.Vin[0].Witness) != test.wantWitness {
+			t.Errorf("%s: %d witness items, want %d", test.name,
+				len(txReply.Vin[0].Witness), test.wantWitness)
+		}
+	}
+}
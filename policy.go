@@ -0,0 +1,162 @@
+package rawdecodebtc
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// DefaultRelayFeePerKb is the minimum relay fee, in satoshi per kilobyte,
+// used to flag dust outputs in decoded results. It matches the btcd and
+// Bitcoin Core default.
+const DefaultRelayFeePerKb = 1000
+
+// maxRBFSequence is the highest input sequence number that still signals
+// opt-in replaceability as defined by BIP125.
+const maxRBFSequence = wire.MaxTxInSequenceNum - 2
+
+// signalsReplacement reports whether any input of mtx opts in to
+// replace-by-fee according to BIP125.
+func signalsReplacement(mtx *wire.MsgTx) bool {
+	for _, txIn := range mtx.TxIn {
+		if txIn.Sequence <= maxRBFSequence {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDustOutput reports whether txOut is dust at the given minimum relay fee
+// in satoshi per kilobyte, following the btcd mempool policy: an output is
+// dust when spending it would cost more than a third of its value in fees, and
+// unspendable outputs are always dust. The size of the spending input is
+// estimated from a typical pay-to-pubkey-hash input, discounted for witness
+// programs.
+func IsDustOutput(txOut *wire.TxOut, relayFeePerKb int64) bool {
+	if txscript.IsUnspendable(txOut.PkScript) {
+		return true
+	}
+
+	// 41 bytes of outpoint, script length and sequence plus 107 bytes of
+	// signature and compressed public key.
+	totalSize := txOut.SerializeSize() + 41
+	if txscript.IsWitnessProgram(txOut.PkScript) {
+		totalSize += 107 / blockchain.WitnessScaleFactor
+	} else {
+		totalSize += 107
+	}
+
+	return txOut.Value*1000/(3*int64(totalSize)) < relayFeePerKb
+}
+
+// checkMoneyRange returns an error wrapping ErrValueOutOfRange when an output
+// value of mtx, or the running total of them, lies outside the range
+// [0, btcutil.MaxSatoshi], as consensus requires.
+func checkMoneyRange(mtx *wire.MsgTx) error {
+	var total int64
+	for i, txOut := range mtx.TxOut {
+		if txOut.Value < 0 || txOut.Value > btcutil.MaxSatoshi {
+			return fmt.Errorf("%w: output %d value %d", ErrValueOutOfRange,
+				i, txOut.Value)
+		}
+		total += txOut.Value
+		if total > btcutil.MaxSatoshi {
+			return fmt.Errorf("%w: total output value %d", ErrValueOutOfRange,
+				total)
+		}
+	}
+	return nil
+}
+
+// Standardness limits applied by IsStandard, matching the Bitcoin Core v28
+// defaults. trucVersion marks a TRUC transaction (BIP431), limited to
+// maxTRUCVsize.
+const (
+	maxStandardVersion       = 3
+	trucVersion              = 3
+	maxTRUCVsize             = 10000
+	maxStandardTxWeight      = 400000
+	maxStandardSigScriptSize = 1650
+	maxStandardMultisigKeys  = 3
+	maxStandardNullDataSize  = 83
+	maxStandardSigOpsCost    = 16000
+)
+
+// IsStandard reports whether mtx passes the common standardness checks nodes
+// apply before relaying a transaction. When it does not, the reason names the
+// first failed check with the Bitcoin Core reject reason:
+//
+//   - "version": a version other than 1, 2 or 3.
+//   - "tx-size": a weight above 400000.
+//   - "scriptsig-size": a scriptSig longer than 1650 bytes.
+//   - "scriptsig-not-pushonly": a scriptSig with other opcodes than pushes.
+//   - "scriptpubkey": a nonstandard output script, a bare multisig with more
+//     than 3 keys or an OP_RETURN script longer than 83 bytes.
+//   - "multi-op-return": more than one OP_RETURN output.
+//   - "dust": an output that is dust at DefaultRelayFeePerKb.
+//   - "bad-txns-too-many-sigops": a legacy sigop cost above 16000.
+//   - "TRUC-violation": a version 3 transaction above 10000 vbytes.
+//
+// Checks needing the spent outputs or the chain state, such as finality,
+// the P2SH sigop count or the TRUC package limits, are not applied. The
+// reason is the error message when the network is not known.
+func IsStandard(mtx *wire.MsgTx, net string) (bool, string) {
+	if _, err := paramsForNet(net); err != nil {
+		return false, err.Error()
+	}
+
+	if mtx.Version < 1 || mtx.Version > maxStandardVersion {
+		return false, "version"
+	}
+
+	tx := btcutil.NewTx(mtx)
+	if blockchain.GetTransactionWeight(tx) > maxStandardTxWeight {
+		return false, "tx-size"
+	}
+
+	for _, txIn := range mtx.TxIn {
+		if len(txIn.SignatureScript) > maxStandardSigScriptSize {
+			return false, "scriptsig-size"
+		}
+		if !txscript.IsPushOnlyScript(txIn.SignatureScript) {
+			return false, "scriptsig-not-pushonly"
+		}
+	}
+
+	nullData := 0
+	for _, txOut := range mtx.TxOut {
+		scriptClass := txscript.GetScriptClass(txOut.PkScript)
+		switch addressType(scriptClass, txOut.PkScript) {
+		case AddressNonstandard:
+			return false, "scriptpubkey"
+		case AddressP2MS:
+			if _, n, _, _ := ParseMultisig(txOut.PkScript); n > maxStandardMultisigKeys {
+				return false, "scriptpubkey"
+			}
+		case AddressNullData:
+			if len(txOut.PkScript) > maxStandardNullDataSize {
+				return false, "scriptpubkey"
+			}
+			nullData++
+			continue
+		}
+		if IsDustOutput(txOut, DefaultRelayFeePerKb) {
+			return false, "dust"
+		}
+	}
+	if nullData > 1 {
+		return false, "multi-op-return"
+	}
+
+	if blockchain.CountSigOps(tx)*blockchain.WitnessScaleFactor > maxStandardSigOpsCost {
+		return false, "bad-txns-too-many-sigops"
+	}
+	if mtx.Version == trucVersion && virtualSize(mtx) > maxTRUCVsize {
+		return false, "TRUC-violation"
+	}
+
+	return true, ""
+}
@@ -0,0 +1,51 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestIsStandardVersion checks the versions accepted by IsStandard, with
+// version 3 TRUC transactions held to their size limit.
+func TestIsStandardVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int32
+		outputs int
+		reason  string
+	}{
+		{name: "version 1", version: 1, outputs: 1},
+		{name: "version 2", version: 2, outputs: 1},
+		{name: "version 3", version: 3, outputs: 1},
+		{name: "version 4", version: 4, outputs: 1, reason: "version"},
+		{name: "large version 2", version: 2, outputs: 400},
+		{name: "large version 3", version: 3, outputs: 400,
+			reason: "TRUC-violation"},
+	}
+
+	raw, err := hex.DecodeString(legacyTxHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mtx wire.MsgTx
+			if err := mtx.Deserialize(bytes.NewReader(raw)); err != nil {
+				t.Fatal(err)
+			}
+			mtx.Version = test.version
+			for len(mtx.TxOut) < test.outputs {
+				mtx.AddTxOut(mtx.TxOut[0])
+			}
+
+			ok, reason := IsStandard(&mtx, "testnet")
+			if ok != (test.reason == "") || reason != test.reason {
+				t.Errorf("IsStandard = %v, %q, want reason %q", ok, reason,
+					test.reason)
+			}
+		})
+	}
+}
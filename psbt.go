@@ -0,0 +1,90 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/psbt"
+)
+
+// FromPSBT decodes the unsigned transaction carried by a base64 encoded
+// BIP174 PSBT. When the PSBT provides the witness or non-witness UTXO of
+// every input, the input total and fee are populated as by
+// FromHexWithPrevouts. The fee rate is then computed against the unsigned
+// transaction and so overestimates the rate of the final one.
+//
+// BIP370 version 2 PSBTs, which describe the transaction through per input
+// and per output records instead, are supported too. Their transaction is
+// rebuilt from the records and a missing required one fails with an error
+// wrapping ErrInvalidPSBT.
+func FromPSBT(psbtBase64 string, net string) (txReply TxRawDecodeResult, err error) {
+	cparam, err := paramsForNet(net)
+	if err != nil {
+		return
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(psbtBase64))
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+		return
+	}
+
+	r, global, version, err := readPSBTGlobal(raw)
+	if err != nil {
+		return
+	}
+	if version == 2 {
+		mtx, prevouts, ok, v2Err := fromPSBTv2(r, global)
+		if v2Err != nil {
+			err = v2Err
+			return
+		}
+		txReply = newTxRawDecodeResult(mtx, cparam, nil)
+		if ok {
+			err = setFee(&txReply, mtx, prevouts, btcutil.AmountBTC)
+		}
+		return
+	}
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(raw), false)
+	if err != nil {
+		return
+	}
+
+	txReply = newTxRawDecodeResult(packet.UnsignedTx, cparam, nil)
+	if prevouts, ok := psbtPrevouts(packet); ok {
+		err = setFee(&txReply, packet.UnsignedTx, prevouts, btcutil.AmountBTC)
+	}
+	return
+}
+
+// psbtPrevouts collects the value of the outputs spent by the PSBT from its
+// input UTXO records. The boolean is false unless every input has one.
+func psbtPrevouts(packet *psbt.Packet) (map[wire.OutPoint]int64, bool) {
+	txIns := packet.UnsignedTx.TxIn
+	if len(packet.Inputs) != len(txIns) {
+		return nil, false
+	}
+
+	prevouts := make(map[wire.OutPoint]int64, len(txIns))
+	for i, in := range packet.Inputs {
+		op := txIns[i].PreviousOutPoint
+		switch {
+		case in.WitnessUtxo != nil:
+			prevouts[op] = in.WitnessUtxo.Value
+
+		case in.NonWitnessUtxo != nil &&
+			in.NonWitnessUtxo.TxHash() == op.Hash &&
+			op.Index < uint32(len(in.NonWitnessUtxo.TxOut)):
+			prevouts[op] = in.NonWitnessUtxo.TxOut[op.Index].Value
+
+		default:
+			return nil, false
+		}
+	}
+	return prevouts, true
+}
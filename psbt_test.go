@@ -0,0 +1,63 @@
+package rawdecodebtc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil"
+)
+
+// TestFromPSBT checks the transaction and fee decoded from the BIP174 test
+// vector, whose non-witness UTXO gives the value of the spent output.
+func TestFromPSBT(t *testing.T) {
+	txReply, err := FromPSBT(bip174PSBT, "mainnet")
+	if err != nil {
+		t.Fatalf("FromPSBT: %v", err)
+	}
+
+	if txReply.Version != 2 || txReply.Locktime != 1257139 {
+		t.Errorf("version %d, locktime %d, want 2, 1257139", txReply.Version,
+			txReply.Locktime)
+	}
+	if len(txReply.Vin) != 1 {
+		t.Fatalf("%d inputs, want 1", len(txReply.Vin))
+	}
+	const wantPrev = "f61b1742ca13176464adb3cb66050c00787bb3a4eead37e985f2df1e37718126"
+	if vin := txReply.Vin[0]; vin.Txid != wantPrev || vin.Vout != 0 ||
+		vin.Sequence != 0xfffffffe {
+		t.Errorf("input spends %s:%d with sequence %#x, want %s:0 and 0xfffffffe",
+			vin.Txid, vin.Vout, vin.Sequence, wantPrev)
+	}
+
+	wantVout := []struct {
+		valueSat   int64
+		scriptType string
+	}{
+		{99999699, "pubkeyhash"},
+		{100000000, "scripthash"},
+	}
+	if len(txReply.Vout) != len(wantVout) {
+		t.Fatalf("%d outputs, want %d", len(txReply.Vout), len(wantVout))
+	}
+	for i, want := range wantVout {
+		vout := txReply.Vout[i]
+		if vout.ValueSat != want.valueSat || vout.ScriptPubKey.Type != want.scriptType {
+			t.Errorf("output %d: %d sat %s, want %d sat %s", i, vout.ValueSat,
+				vout.ScriptPubKey.Type, want.valueSat, want.scriptType)
+		}
+	}
+
+	if txReply.Fee == nil {
+		t.Fatal("fee not computed from the non-witness UTXO")
+	}
+	if fee, _ := btcutil.NewAmount(*txReply.Fee); fee != 301 {
+		t.Errorf("fee %d sat, want 301", fee)
+	}
+	if txReply.TotalIn != 2 {
+		t.Errorf("input total %v BTC, want 2", txReply.TotalIn)
+	}
+
+	if _, err := FromPSBT("not base64!", "mainnet"); !errors.Is(err, ErrInvalidBase64) {
+		t.Errorf("invalid base64: error %v, want ErrInvalidBase64", err)
+	}
+}
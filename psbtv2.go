@@ -0,0 +1,307 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtMagic opens every serialized PSBT.
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// Key types of the PSBT records read by fromPSBTv2, as defined in BIP174 and
+// BIP370.
+const (
+	psbtGlobalUnsignedTx         = 0x00
+	psbtGlobalTxVersion          = 0x02
+	psbtGlobalFallbackLocktime   = 0x03
+	psbtGlobalInputCount         = 0x04
+	psbtGlobalOutputCount        = 0x05
+	psbtGlobalVersion            = 0xfb
+	psbtInNonWitnessUtxo         = 0x00
+	psbtInWitnessUtxo            = 0x01
+	psbtInPreviousTxid           = 0x0e
+	psbtInOutputIndex            = 0x0f
+	psbtInSequence               = 0x10
+	psbtInRequiredTimeLocktime   = 0x11
+	psbtInRequiredHeightLocktime = 0x12
+	psbtOutAmount                = 0x03
+	psbtOutScript                = 0x04
+)
+
+// psbtMap holds the records of a PSBT map whose key is a bare type, keyed by
+// that type. Records with key data are not needed and left out.
+type psbtMap map[byte][]byte
+
+// readPSBTGlobal checks the magic of the serialized PSBT raw and reads its
+// global map, returning the reader positioned on the first input map and the
+// PSBT version.
+func readPSBTGlobal(raw []byte) (*bytes.Reader, psbtMap, uint32, error) {
+	if !bytes.HasPrefix(raw, psbtMagic) {
+		return nil, nil, 0, fmt.Errorf("%w: missing magic", ErrInvalidPSBT)
+	}
+
+	r := bytes.NewReader(raw[len(psbtMagic):])
+	global, err := readPSBTMap(r)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var version uint32
+	if v, ok := global[psbtGlobalVersion]; ok {
+		if len(v) != 4 {
+			return nil, nil, 0, fmt.Errorf("%w: malformed version",
+				ErrInvalidPSBT)
+		}
+		version = binary.LittleEndian.Uint32(v)
+	}
+	return r, global, version, nil
+}
+
+// readPSBTMap reads a PSBT map up to its terminating zero byte.
+func readPSBTMap(r *bytes.Reader) (psbtMap, error) {
+	m := make(psbtMap)
+	for {
+		key, err := readPSBTBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 {
+			return m, nil
+		}
+
+		value, err := readPSBTBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(key) > 1 {
+			continue
+		}
+		if _, exists := m[key[0]]; exists {
+			return nil, fmt.Errorf("%w: duplicate key type %#x",
+				ErrInvalidPSBT, key[0])
+		}
+		m[key[0]] = value
+	}
+}
+
+// readPSBTBytes reads a CompactSize length prefixed field of r.
+func readPSBTBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil || n > uint64(r.Len()) {
+		return nil, fmt.Errorf("%w: truncated record", ErrInvalidPSBT)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("%w: truncated record", ErrInvalidPSBT)
+	}
+	return b, nil
+}
+
+// fromPSBTv2 rebuilds the transaction described by the global map and the
+// input and output maps read from r of a BIP370 PSBT, and the value of the
+// outputs it spends when every input has a UTXO record. The boolean is false
+// when values are missing.
+func fromPSBTv2(r *bytes.Reader, global psbtMap) (*wire.MsgTx, map[wire.OutPoint]int64, bool, error) {
+	if _, ok := global[psbtGlobalUnsignedTx]; ok {
+		return nil, nil, false, fmt.Errorf("%w: version 2 with an unsigned "+
+			"transaction", ErrInvalidPSBT)
+	}
+
+	version, err := psbtUint32(global, psbtGlobalTxVersion, "global", 0)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	numIn, err := psbtCount(global, psbtGlobalInputCount)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	numOut, err := psbtCount(global, psbtGlobalOutputCount)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// Every map takes at least its terminating byte, so counts beyond the
+	// bytes left are rejected before anything is sized from them.
+	if uint64(numIn)+uint64(numOut) > uint64(r.Len()) {
+		return nil, nil, false, fmt.Errorf("%w: %d inputs and %d outputs "+
+			"declared with %d bytes left", ErrInvalidPSBT, numIn, numOut,
+			r.Len())
+	}
+
+	mtx := wire.NewMsgTx(int32(version))
+	prevouts := make(map[wire.OutPoint]int64)
+	havePrevouts := true
+	var inputs []psbtMap
+	for i := 0; i < numIn; i++ {
+		in, err := readPSBTMap(r)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		inputs = append(inputs, in)
+
+		txid, ok := in[psbtInPreviousTxid]
+		if !ok || len(txid) != chainhash.HashSize {
+			return nil, nil, false, fmt.Errorf("%w: input %d lacks a "+
+				"previous txid", ErrInvalidPSBT, i)
+		}
+		index, err := psbtUint32(in, psbtInOutputIndex, "input", i)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		sequence := wire.MaxTxInSequenceNum
+		if _, ok := in[psbtInSequence]; ok {
+			sequence, err = psbtUint32(in, psbtInSequence, "input", i)
+			if err != nil {
+				return nil, nil, false, err
+			}
+		}
+
+		var hash chainhash.Hash
+		copy(hash[:], txid)
+		op := wire.NewOutPoint(&hash, index)
+		txIn := wire.NewTxIn(op, nil, nil)
+		txIn.Sequence = sequence
+		mtx.AddTxIn(txIn)
+
+		if value, ok := psbtUtxoValue(in, *op); ok {
+			prevouts[*op] = value
+		} else {
+			havePrevouts = false
+		}
+	}
+
+	for i := 0; i < numOut; i++ {
+		out, err := readPSBTMap(r)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		amount, ok := out[psbtOutAmount]
+		if !ok || len(amount) != 8 {
+			return nil, nil, false, fmt.Errorf("%w: output %d lacks an "+
+				"amount", ErrInvalidPSBT, i)
+		}
+		script, ok := out[psbtOutScript]
+		if !ok {
+			return nil, nil, false, fmt.Errorf("%w: output %d lacks a "+
+				"script", ErrInvalidPSBT, i)
+		}
+		value := int64(binary.LittleEndian.Uint64(amount))
+		mtx.AddTxOut(wire.NewTxOut(value, script))
+	}
+
+	mtx.LockTime, err = psbtLocktime(global, inputs)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return mtx, prevouts, havePrevouts, nil
+}
+
+// psbtUint32 reads the little endian uint32 record typ of m, the map of the
+// index-th entry of the given kind.
+func psbtUint32(m psbtMap, typ byte, kind string, index int) (uint32, error) {
+	v, ok := m[typ]
+	if !ok || len(v) != 4 {
+		return 0, fmt.Errorf("%w: %s %d lacks a valid record %#x",
+			ErrInvalidPSBT, kind, index, typ)
+	}
+	return binary.LittleEndian.Uint32(v), nil
+}
+
+// psbtCount reads the CompactSize count record typ of the global map.
+func psbtCount(global psbtMap, typ byte) (int, error) {
+	v, ok := global[typ]
+	if !ok {
+		return 0, fmt.Errorf("%w: missing global record %#x",
+			ErrInvalidPSBT, typ)
+	}
+
+	r := bytes.NewReader(v)
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil || r.Len() != 0 || n > uint64(wire.MaxTxInSequenceNum) {
+		return 0, fmt.Errorf("%w: malformed global record %#x",
+			ErrInvalidPSBT, typ)
+	}
+	return int(n), nil
+}
+
+// psbtUtxoValue returns the value of the output op spent by the input map in,
+// from its witness or non-witness UTXO record.
+func psbtUtxoValue(in psbtMap, op wire.OutPoint) (int64, bool) {
+	if utxo, ok := in[psbtInWitnessUtxo]; ok && len(utxo) >= 8 {
+		return int64(binary.LittleEndian.Uint64(utxo)), true
+	}
+
+	utxo, ok := in[psbtInNonWitnessUtxo]
+	if !ok {
+		return 0, false
+	}
+	var prevTx wire.MsgTx
+	if err := prevTx.Deserialize(bytes.NewReader(utxo)); err != nil ||
+		prevTx.TxHash() != op.Hash || op.Index >= uint32(len(prevTx.TxOut)) {
+		return 0, false
+	}
+	return prevTx.TxOut[op.Index].Value, true
+}
+
+// psbtLocktime determines the locktime of a BIP370 PSBT: the fallback
+// locktime, zero by default, unless inputs require a locktime. The largest
+// required height is then used when every such input accepts a height, or
+// else the largest required time when they all accept a time.
+func psbtLocktime(global psbtMap, inputs []psbtMap) (uint32, error) {
+	var maxHeight, maxTime uint32
+	constrained, allHeight, allTime := false, true, true
+	for i, in := range inputs {
+		_, hasHeight := in[psbtInRequiredHeightLocktime]
+		_, hasTime := in[psbtInRequiredTimeLocktime]
+		if !hasHeight && !hasTime {
+			continue
+		}
+		constrained = true
+
+		if hasHeight {
+			height, err := psbtUint32(in, psbtInRequiredHeightLocktime,
+				"input", i)
+			if err != nil {
+				return 0, err
+			}
+			if height > maxHeight {
+				maxHeight = height
+			}
+		} else {
+			allHeight = false
+		}
+		if hasTime {
+			t, err := psbtUint32(in, psbtInRequiredTimeLocktime, "input", i)
+			if err != nil {
+				return 0, err
+			}
+			if t > maxTime {
+				maxTime = t
+			}
+		} else {
+			allTime = false
+		}
+	}
+
+	switch {
+	case !constrained:
+		if _, ok := global[psbtGlobalFallbackLocktime]; !ok {
+			return 0, nil
+		}
+		return psbtUint32(global, psbtGlobalFallbackLocktime, "global", 0)
+	case allHeight:
+		return maxHeight, nil
+	case allTime:
+		return maxTime, nil
+	default:
+		return 0, fmt.Errorf("%w: inputs require incompatible locktimes",
+			ErrInvalidPSBT)
+	}
+}
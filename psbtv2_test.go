@@ -0,0 +1,45 @@
+package rawdecodebtc
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// TestFromPSBTv2DeclaredCounts checks that input and output counts larger
+// than the PSBT can hold are rejected before anything is allocated from them.
+func TestFromPSBTv2DeclaredCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []byte
+	}{
+		{
+			name: "huge input count",
+			counts: []byte{
+				0x01, psbtGlobalInputCount, 0x05, 0xfe, 0xf0, 0xff, 0xff, 0xff,
+				0x01, psbtGlobalOutputCount, 0x01, 0x00,
+			},
+		},
+		{
+			name: "huge output count",
+			counts: []byte{
+				0x01, psbtGlobalInputCount, 0x01, 0x00,
+				0x01, psbtGlobalOutputCount, 0x05, 0xfe, 0xf0, 0xff, 0xff, 0xff,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		raw := append([]byte{}, psbtMagic...)
+		raw = append(raw, 0x01, psbtGlobalVersion, 0x04, 0x02, 0x00, 0x00, 0x00)
+		raw = append(raw, 0x01, psbtGlobalTxVersion, 0x04, 0x02, 0x00, 0x00, 0x00)
+		raw = append(raw, test.counts...)
+		raw = append(raw, 0x00)
+
+		_, err := FromPSBT(base64.StdEncoding.EncodeToString(raw), "mainnet")
+		if !errors.Is(err, ErrInvalidPSBT) {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				ErrInvalidPSBT)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package rawdecodebtc
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// PublicKeys returns the public keys referenced by the decoded transaction,
+// deduplicated in order of appearance. Outputs contribute the key of P2PK
+// scripts and the keys of bare multisig scripts. Inputs contribute the pushes
+// of their scriptSig and the elements of their witness that are shaped like a
+// public key, see isPubKeyLike, and the keys of a multisig redeem or witness
+// script. The input side is heuristic: the spent output is not known, so
+// arbitrary data shaped like a key cannot be told apart from one.
+func (r TxRawDecodeResult) PublicKeys() [][]byte {
+	var keys [][]byte
+	seen := make(map[string]struct{})
+	add := func(candidates ...[]byte) {
+		for _, key := range candidates {
+			if !isPubKeyLike(key) {
+				continue
+			}
+			if _, exists := seen[string(key)]; exists {
+				continue
+			}
+			seen[string(key)] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	addMultisig := func(script []byte, ok bool) {
+		if !ok {
+			return
+		}
+		if _, _, pubKeys, ok := ParseMultisig(script); ok {
+			add(pubKeys...)
+		}
+	}
+
+	for _, vin := range r.Vin {
+		if vin.IsCoinBase() {
+			continue
+		}
+
+		var sigScript []byte
+		if vin.ScriptSig != nil {
+			sigScript, _ = hex.DecodeString(vin.ScriptSig.Hex)
+		}
+		witness := make(wire.TxWitness, 0, len(vin.Witness))
+		for _, item := range vin.Witness {
+			if b, err := hex.DecodeString(item); err == nil {
+				witness = append(witness, b)
+			}
+		}
+
+		if pushes, err := txscript.PushedData(sigScript); err == nil {
+			add(pushes...)
+		}
+		add(witness...)
+		addMultisig(RedeemScript(sigScript))
+		addMultisig(WitnessScript(witness))
+	}
+
+	for _, vout := range r.Vout {
+		pkScript, err := hex.DecodeString(vout.ScriptPubKey.Hex)
+		if err != nil {
+			continue
+		}
+		switch txscript.GetScriptClass(pkScript) {
+		case txscript.PubKeyTy:
+			pushes, _ := txscript.PushedData(pkScript)
+			add(pushes...)
+		case txscript.MultiSigTy:
+			addMultisig(pkScript, true)
+		}
+	}
+
+	return keys
+}
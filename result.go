@@ -0,0 +1,169 @@
+package rawdecodebtc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// ScriptTypeCounts returns the number of outputs of each scriptPubKey type,
+// keyed by the Type reported in Vout. Outputs removed by an address filter
+// are not counted.
+func (r TxRawDecodeResult) ScriptTypeCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, vout := range r.Vout {
+		counts[vout.ScriptPubKey.Type]++
+	}
+	return counts
+}
+
+// Addresses returns the addresses paid by the outputs of the decoded
+// transaction, in output order with duplicates removed. Outputs removed by an
+// address filter are not included.
+func (r TxRawDecodeResult) Addresses() []string {
+	var addrs []string
+	seen := make(map[string]struct{})
+	for _, vout := range r.Vout {
+		for _, addr := range vout.ScriptPubKey.Addresses {
+			if _, exists := seen[addr]; exists {
+				continue
+			}
+			seen[addr] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// PrettyJSON returns the JSON encoding of the decoded transaction indented
+// with two spaces, for command line output. Empty witnesses are omitted as in
+// the compact encoding.
+func (r TxRawDecodeResult) PrettyJSON() (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// OutputsForAddress returns the outputs of the decoded transaction paying
+// addr. Unlike an address filter it leaves the result untouched, so one
+// decode can serve many queries.
+func (r TxRawDecodeResult) OutputsForAddress(addr string) []Vout {
+	var vouts []Vout
+	for _, vout := range r.Vout {
+		for _, a := range vout.ScriptPubKey.Addresses {
+			if a == addr {
+				vouts = append(vouts, vout)
+				break
+			}
+		}
+	}
+	return vouts
+}
+
+// Hash returns the txid of the decoded transaction as a chainhash.Hash, for
+// callers such as merkle proof checkers that need it in binary form. It is
+// the zero hash if Txid is not a valid hash string.
+func (r TxRawDecodeResult) Hash() chainhash.Hash {
+	if r.mtx != nil {
+		return r.mtx.TxHash()
+	}
+	return hashFromStr(r.Txid)
+}
+
+// WitnessHash returns the wtxid of the decoded transaction as a
+// chainhash.Hash. It is the zero hash if Wtxid is not a valid hash string.
+func (r TxRawDecodeResult) WitnessHash() chainhash.Hash {
+	if r.mtx != nil {
+		return r.mtx.WitnessHash()
+	}
+	return hashFromStr(r.Wtxid)
+}
+
+// Fingerprint returns the canonical key for deduplicating decoded
+// transactions, their txid. The txid commits to the version, inputs, outputs
+// and locktime but not to the witness, so it is stable across the witness
+// malleation anyone relaying a segwit transaction can apply, and is unique
+// among confirmed transactions. It is the key to use for confirmed
+// transactions and for recognizing the same payment. The scriptSig of legacy
+// inputs is committed to and remains malleable, so a legacy transaction may
+// still show up under several fingerprints until it confirms.
+func (r TxRawDecodeResult) Fingerprint() string {
+	return r.Txid
+}
+
+// WitnessFingerprint returns the wtxid of the decoded transaction, which
+// commits to the witness as well. It tells apart copies of a transaction
+// whose witnesses differ and that nodes relay as distinct announcements, so
+// it is the key to use for mempool and relay level deduplication of the
+// exact bytes seen. It equals Fingerprint for transactions without witness.
+func (r TxRawDecodeResult) WitnessFingerprint() string {
+	return r.Wtxid
+}
+
+// hashFromStr parses the byte-reversed hex hash s, returning the zero hash
+// when s is invalid.
+func hashFromStr(s string) chainhash.Hash {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return chainhash.Hash{}
+	}
+	return *hash
+}
+
+// PrevOutpointRaw returns the outpoints spent by the decoded transaction in
+// their serialized form: the previous txid in internal byte order, reversed
+// from Vin.Txid, followed by the output index as a little endian uint32. This
+// is how UTXO databases commonly key outputs. A coinbase spends no outpoint
+// and yields an empty slice.
+func (r TxRawDecodeResult) PrevOutpointRaw() [][36]byte {
+	raw := make([][36]byte, 0, len(r.Vin))
+	for _, vin := range r.Vin {
+		if vin.IsCoinBase() {
+			continue
+		}
+
+		var key [36]byte
+		hash := hashFromStr(vin.Txid)
+		copy(key[:chainhash.HashSize], hash[:])
+		binary.LittleEndian.PutUint32(key[chainhash.HashSize:], vin.Vout)
+		raw = append(raw, key)
+	}
+	return raw
+}
+
+// Witness kinds returned by WitnessKind.
+const (
+	WitnessKindLegacy   = "legacy"
+	WitnessKindSegwit   = "segwit"
+	WitnessKindMixed    = "mixed"
+	WitnessKindCoinbase = "coinbase"
+)
+
+// WitnessKind tells whether the inputs of the decoded transaction all carry
+// a witness, WitnessKindSegwit, none does, WitnessKindLegacy, or some do,
+// WitnessKindMixed. A transaction without inputs is legacy. The witness of a
+// coinbase only holds the witness reserved value, so coinbases are reported
+// as WitnessKindCoinbase instead.
+func (r TxRawDecodeResult) WitnessKind() string {
+	if r.IsCoinbase {
+		return WitnessKindCoinbase
+	}
+
+	var segwit int
+	for _, vin := range r.Vin {
+		if len(vin.Witness) > 0 {
+			segwit++
+		}
+	}
+	switch {
+	case segwit == 0:
+		return WitnessKindLegacy
+	case segwit == len(r.Vin):
+		return WitnessKindSegwit
+	default:
+		return WitnessKindMixed
+	}
+}
@@ -0,0 +1,230 @@
+package rawdecodebtc
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// ClassifyScript returns the script class, the encoded addresses and the
+// number of required signatures of pkScript for the given network, exactly
+// as reported in the scriptPubKey of a decoded output. The error is non-nil
+// when the network is unknown or the script fails to parse.
+func ClassifyScript(pkScript []byte, net string) (class string, addrs []string, reqSigs int, err error) {
+	chainParams, err := paramsForNet(net)
+	if err != nil {
+		return
+	}
+
+	scriptClass, addresses, reqSigs, err := txscript.ExtractPkScriptAddrs(
+		pkScript, chainParams)
+	return scriptClass.String(), encodeAddresses(addresses, nil), reqSigs, err
+}
+
+// encodeAddresses returns the string encoding of every address in addrs, as
+// made by encode or by the EncodeAddress method when encode is nil.
+func encodeAddresses(addrs []btcutil.Address, encode func(btcutil.Address) string) []string {
+	encodedAddrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		if encode != nil {
+			encodedAddrs[i] = encode(addr)
+		} else {
+			encodedAddrs[i] = addr.EncodeAddress()
+		}
+	}
+	return encodedAddrs
+}
+
+// passesFilter reports whether any of the encoded addresses is part of the
+// filter. An empty filter lets everything through.
+func passesFilter(encodedAddrs []string, filterAddrMap map[string]struct{}) bool {
+	if len(filterAddrMap) == 0 {
+		return true
+	}
+	for _, encodedAddr := range encodedAddrs {
+		if _, exists := filterAddrMap[encodedAddr]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptHashInFilter reports whether pkScript is a P2SH or P2WSH output of
+// class scriptClass whose script hash, in lowercase hex, is part of the
+// filter.
+func scriptHashInFilter(scriptClass txscript.ScriptClass, pkScript []byte, filterAddrMap map[string]struct{}) bool {
+	var scriptHash []byte
+	switch scriptClass {
+	case txscript.ScriptHashTy:
+		// OP_HASH160 <20 byte hash> OP_EQUAL
+		scriptHash = pkScript[2:22]
+	case txscript.WitnessV0ScriptHashTy:
+		// OP_0 <32 byte hash>
+		scriptHash = pkScript[2:34]
+	default:
+		return false
+	}
+
+	_, exists := filterAddrMap[hex.EncodeToString(scriptHash)]
+	return exists
+}
+
+// OpReturnPushes returns the data pushes following the OP_RETURN opcode of
+// pkScript, in script order. The boolean is false when pkScript is not an
+// OP_RETURN output. A bare OP_RETURN yields no pushes.
+func OpReturnPushes(pkScript []byte) ([][]byte, bool) {
+	if len(pkScript) == 0 || pkScript[0] != txscript.OP_RETURN {
+		return nil, false
+	}
+
+	// The remainder of the script may be malformed, in which case there
+	// is no data that can be reliably extracted.
+	pushes, err := txscript.PushedData(pkScript[1:])
+	if err != nil {
+		return nil, true
+	}
+	return pushes, true
+}
+
+// DefaultMaxDataCarrierSize is the largest amount of data, in bytes, an
+// OP_RETURN output may carry under the default relay policy.
+const DefaultMaxDataCarrierSize = 80
+
+// DataCarrierSize returns the number of data bytes pushed by the OP_RETURN
+// output script pkScript, the amount limited by the data carrier relay
+// policy. The boolean is false when pkScript is not an OP_RETURN output.
+func DataCarrierSize(pkScript []byte) (int, bool) {
+	pushes, ok := OpReturnPushes(pkScript)
+	if !ok {
+		return 0, false
+	}
+
+	var size int
+	for _, push := range pushes {
+		size += len(push)
+	}
+	return size, true
+}
+
+// ExtractOpReturns returns the payload of every OP_RETURN output of mtx, in
+// output order. Each payload is the concatenation of the data pushed by that
+// output, so an output carrying several pushes yields a single entry and a
+// bare OP_RETURN yields an empty one. Use OpReturnPushes to keep the push
+// boundaries.
+func ExtractOpReturns(mtx *wire.MsgTx) [][]byte {
+	var payloads [][]byte
+	for _, txOut := range mtx.TxOut {
+		pushes, ok := OpReturnPushes(txOut.PkScript)
+		if !ok {
+			continue
+		}
+
+		payload := make([]byte, 0)
+		for _, push := range pushes {
+			payload = append(payload, push...)
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+// ParseMultisig returns the number of required signatures m, the number of
+// keys n and the public keys of a standard m-of-n multisig script. It accepts
+// bare multisig output scripts as well as the redeem or witness script
+// revealed when spending a P2SH or P2WSH multisig output. The boolean is
+// false for any other script.
+func ParseMultisig(pkScript []byte) (m, n int, pubKeys [][]byte, ok bool) {
+	if txscript.GetScriptClass(pkScript) != txscript.MultiSigTy {
+		return 0, 0, nil, false
+	}
+
+	n, m, err := txscript.CalcMultiSigStats(pkScript)
+	if err != nil {
+		return 0, 0, nil, false
+	}
+
+	// The only data pushes of a standard multisig script are its keys.
+	pubKeys, err = txscript.PushedData(pkScript)
+	if err != nil || len(pubKeys) != n {
+		return 0, 0, nil, false
+	}
+	return m, n, pubKeys, true
+}
+
+// RedeemScript returns the redeem script revealed by a P2SH scriptSig, its
+// last data push. The spent output is not known, so sigScript is accepted
+// when it is push only and its last push is neither a signature nor a public
+// key, as for P2PKH and P2PK spends, and parses as a script.
+func RedeemScript(sigScript []byte) ([]byte, bool) {
+	if len(sigScript) == 0 || !txscript.IsPushOnlyScript(sigScript) {
+		return nil, false
+	}
+
+	pushes, err := txscript.PushedData(sigScript)
+	if err != nil || len(pushes) == 0 {
+		return nil, false
+	}
+	return embeddedScript(pushes[len(pushes)-1])
+}
+
+// WitnessScript returns the witness script revealed by a P2WSH witness, its
+// last element. Like RedeemScript it is a shape check: P2WPKH witnesses,
+// which end with a public key, and taproot witnesses are rejected.
+func WitnessScript(witness wire.TxWitness) ([]byte, bool) {
+	if len(witness) == 0 {
+		return nil, false
+	}
+	if _, ok := ParseTaprootWitness(witness); ok {
+		return nil, false
+	}
+	return embeddedScript(witness[len(witness)-1])
+}
+
+// embeddedScript reports whether the pushed data b looks like a script
+// rather than a signature or public key.
+func embeddedScript(b []byte) ([]byte, bool) {
+	if len(b) == 0 || isPubKeyLike(b) || isSignatureLike(b) {
+		return nil, false
+	}
+	if _, err := txscript.DisasmString(b); err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// isPubKeyLike reports whether b has the size and prefix of a serialized
+// compressed or uncompressed secp256k1 public key.
+func isPubKeyLike(b []byte) bool {
+	switch len(b) {
+	case 33:
+		return b[0] == 0x02 || b[0] == 0x03
+	case 65:
+		return b[0] == 0x04
+	}
+	return false
+}
+
+// isSignatureLike reports whether b has the framing of a DER encoded ECDSA
+// signature followed by a sighash type byte.
+func isSignatureLike(b []byte) bool {
+	// 0x30 <total length> ... <sighash type>
+	return len(b) >= 9 && len(b) <= 73 && b[0] == 0x30 &&
+		int(b[1]) == len(b)-3
+}
+
+// reqSigsKnown reports whether the number of required signatures returned by
+// txscript.ExtractPkScriptAddrs for class is exact. That holds for key and
+// bare multisig outputs, where m is read from the script. Script hash outputs
+// are reported as requiring one signature although the actual number depends
+// on the unrevealed script, and null data and nonstandard outputs report
+// zero, so the value is unknown for all of those.
+func reqSigsKnown(class txscript.ScriptClass) bool {
+	switch class {
+	case txscript.PubKeyTy, txscript.PubKeyHashTy,
+		txscript.WitnessV0PubKeyHashTy, txscript.MultiSigTy:
+		return true
+	}
+	return false
+}
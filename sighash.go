@@ -0,0 +1,93 @@
+package rawdecodebtc
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// Base signature hash types reported in SighashInfo.Base.
+const (
+	SighashAll     = "ALL"
+	SighashNone    = "NONE"
+	SighashSingle  = "SINGLE"
+	SighashUnknown = "UNKNOWN"
+)
+
+// SighashInfo describes the signature hash type of a signature found in an
+// input.
+type SighashInfo struct {
+	// Input is the index of the input carrying the signature.
+	Input int `json:"input"`
+
+	// Signature is the signature in hex, hash type byte included.
+	Signature string `json:"signature"`
+
+	// HashType is the raw hash type byte ending the signature.
+	HashType byte `json:"hashtype"`
+
+	// Base is the hash type without the ANYONECANPAY flag, one of
+	// SighashAll, SighashNone, SighashSingle or SighashUnknown.
+	Base string `json:"base"`
+
+	// AnyoneCanPay is set when the signature commits to its own input
+	// only.
+	AnyoneCanPay bool `json:"anyonecanpay"`
+}
+
+// ParseSighashFlags returns the hash type of every signature found in the
+// inputs of r, in input order and, within an input, scriptSig pushes before
+// witness elements. Signatures are recognized heuristically as pushes and
+// witness elements with the DER framing of an ECDSA signature, see
+// isSignatureLike, so arbitrary data of that shape is reported too, while the
+// Schnorr signatures of taproot spends, which have no framing, are not.
+func ParseSighashFlags(r TxRawDecodeResult) []SighashInfo {
+	var infos []SighashInfo
+	for i, vin := range r.Vin {
+		if vin.IsCoinBase() {
+			continue
+		}
+
+		var elements [][]byte
+		if vin.ScriptSig != nil {
+			sigScript, _ := hex.DecodeString(vin.ScriptSig.Hex)
+			if pushes, err := txscript.PushedData(sigScript); err == nil {
+				elements = append(elements, pushes...)
+			}
+		}
+		for _, item := range vin.Witness {
+			if b, err := hex.DecodeString(item); err == nil {
+				elements = append(elements, b)
+			}
+		}
+
+		for _, e := range elements {
+			if !isSignatureLike(e) {
+				continue
+			}
+			hashType := txscript.SigHashType(e[len(e)-1])
+			infos = append(infos, SighashInfo{
+				Input:        i,
+				Signature:    hex.EncodeToString(e),
+				HashType:     byte(hashType),
+				Base:         sighashBase(hashType),
+				AnyoneCanPay: hashType&txscript.SigHashAnyOneCanPay != 0,
+			})
+		}
+	}
+	return infos
+}
+
+// sighashBase names the base hash type of hashType.
+func sighashBase(hashType txscript.SigHashType) string {
+	switch hashType & sigHashMask {
+	case txscript.SigHashAll:
+		return SighashAll
+	case txscript.SigHashNone:
+		return SighashNone
+	case txscript.SigHashSingle:
+		return SighashSingle
+	default:
+		return SighashUnknown
+	}
+}
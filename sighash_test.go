@@ -0,0 +1,58 @@
+package rawdecodebtc
+
+import "testing"
+
+// bip143SighashTxHex is the BIP143 example of a P2SH-P2WSH 6-of-6 multisig
+// spend, signed with a different hash type per signature.
+const bip143SighashTxHex = "0100000000010136641869ca081e70f394c6948e8af409e18b619df2ed74aa106c1ca29787b96e0100000023220020a16b5755f7f6f96dbd65f5f0d6ab9418b89af4b1f14a1bb8a09062c35f0dcb54ffffffff0200e9a435000000001976a914389ffce9cd9ae88dcc0631e88a821ffdbe9bfe2688acc0832f05000000001976a9147480a33f950689af511e6e84c138dbbd3c3ee41588ac080047304402206ac44d672dac41f9b00e28f4df20c52eeb087207e8d758d76d92c6fab3b73e2b0220367750dbbe19290069cba53d096f44530e4f98acaa594810388cf7409a1870ce01473044022068c7946a43232757cbdf9176f009a928e1cd9a1a8c212f15c1e11ac9f2925d9002205b75f937ff2f9f3c1246e547e54f62e027f64eefa2695578cc6432cdabce271502473044022059ebf56d98010a932cf8ecfec54c48e6139ed6adb0728c09cbe1e4fa0915302e022007cd986c8fa870ff5d2b3a89139c9fe7e499259875357e20fcbb15571c76795403483045022100fbefd94bd0a488d50b79102b5dad4ab6ced30c4069f1eaa69a4b5a763414067e02203156c6a5c9cf88f91265f5a942e96213afae16d83321c8b31bb342142a14d16381483045022100a5263ea0553ba89221984bd7f0b13613db16e7a70c549a86de0cc0444141a407022005c360ef0ae5a5d4f9f2f87a56c1546cc8268cab08c73501d6b3be2e1e1a8a08824730440220525406a1482936d5a21888260dc165497a90a15669636d8edca6b9fe490d309c022032af0c646a34a44d1f4576bf6a4a74b67940f8faa84c7df9abe12a01a11e2b4783cf56210307b8ae49ac90a048e9b53357a2354b3334e9c8bee813ecb98e99a7e07e8c3ba32103b28f0c28bfab54554ae8c658ac5c3e0ce6e79ad336331f78c428dd43eea8449b21034b8113d703413d57761b8b9781957b8c0ac1dfe69f492580ca4195f50376ba4a21033400f6afecb833092a9a21cfdf1ed1376e58c5d1f47de74683123987e967a8f42103a6d48b1131e94ba04d9737d61acdaa1322008af9602b3b14862c07a1789aac162102d8b661b0b3302ee2f162b09e07a55ad5dfbe673a9f01d9f0c19617681024306b56ae00000000"
+
+// TestParseSighashFlags checks the hash types reported for the signatures of
+// known transactions.
+func TestParseSighashFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []SighashInfo
+	}{
+		{
+			name:    "P2PKH scriptSig",
+			message: legacyTxHex,
+			want: []SighashInfo{
+				{HashType: 0x01, Base: SighashAll},
+			},
+		},
+		{
+			name:    "BIP143 witness",
+			message: bip143SighashTxHex,
+			want: []SighashInfo{
+				{HashType: 0x01, Base: SighashAll},
+				{HashType: 0x02, Base: SighashNone},
+				{HashType: 0x03, Base: SighashSingle},
+				{HashType: 0x81, Base: SighashAll, AnyoneCanPay: true},
+				{HashType: 0x82, Base: SighashNone, AnyoneCanPay: true},
+				{HashType: 0x83, Base: SighashSingle, AnyoneCanPay: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		txReply, err := FromHex(test.message, "mainnet")
+		if err != nil {
+			t.Fatalf("%s: FromHex: %v", test.name, err)
+		}
+		got := ParseSighashFlags(txReply)
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: %d signatures, want %d", test.name, len(got),
+				len(test.want))
+		}
+		for i, info := range got {
+			want := test.want[i]
+			if info.Input != 0 || info.HashType != want.HashType ||
+				info.Base != want.Base || info.AnyoneCanPay != want.AnyoneCanPay {
+				t.Errorf("%s: signature %d: got input %d %#x %s %v, want %#x %s %v",
+					test.name, i, info.Input, info.HashType, info.Base,
+					info.AnyoneCanPay, want.HashType, want.Base, want.AnyoneCanPay)
+			}
+		}
+	}
+}
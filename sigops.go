@@ -0,0 +1,48 @@
+package rawdecodebtc
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// SigOpCount returns the legacy signature operation count of mtx, counted
+// over its scriptSigs and output scripts as blockchain.CountSigOps does. The
+// sigops of P2SH redeem scripts and witness scripts depend on the spent
+// outputs and are not included, see SigOpCost for those. It is what
+// TxRawDecodeResult.SigOps reports.
+func SigOpCount(mtx *wire.MsgTx) int {
+	return blockchain.CountSigOps(btcutil.NewTx(mtx))
+}
+
+// SigOpCost returns the segwit weighted signature operation cost of mtx, as
+// limited per block by consensus: the legacy and P2SH sigops scaled by the
+// witness scale factor plus the witness sigops. prevScripts supplies the
+// pkScript of every outpoint spent by mtx, and a missing one is an error
+// unless mtx is a coinbase.
+func SigOpCost(mtx *wire.MsgTx, prevScripts map[wire.OutPoint][]byte) (int, error) {
+	cost := SigOpCount(mtx) * blockchain.WitnessScaleFactor
+	if blockchain.IsCoinBaseTx(mtx) {
+		return cost, nil
+	}
+
+	for _, txIn := range mtx.TxIn {
+		pkScript, ok := prevScripts[txIn.PreviousOutPoint]
+		if !ok {
+			return 0, fmt.Errorf("missing prevout script for input %v",
+				txIn.PreviousOutPoint)
+		}
+
+		if txscript.IsPayToScriptHash(pkScript) {
+			p2shSigOps := txscript.GetPreciseSigOpCount(
+				txIn.SignatureScript, pkScript, true)
+			cost += p2shSigOps * blockchain.WitnessScaleFactor
+		}
+		cost += txscript.GetWitnessSigOpCount(txIn.SignatureScript,
+			pkScript, txIn.Witness)
+	}
+	return cost, nil
+}
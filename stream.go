@@ -0,0 +1,129 @@
+package rawdecodebtc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeAll decodes every raw transaction of r, which holds transactions
+// concatenated back to back without length prefixes. It stops at an EOF
+// falling on a transaction boundary and returns the transactions decoded so
+// far along with the error if the stream ends inside a transaction or holds
+// malformed data.
+func DecodeAll(r io.Reader, net string) ([]TxRawDecodeResult, error) {
+	cfg, err := newDecodeConfig([]Option{WithNetwork(net)})
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []TxRawDecodeResult
+	for {
+		txReply, err := fromReader(r, cfg)
+		if err != nil {
+			if isCleanEOF(err) {
+				return txs, nil
+			}
+			return txs, err
+		}
+		txs = append(txs, txReply)
+	}
+}
+
+// isCleanEOF reports whether err is an EOF hit before any byte of a new
+// transaction was read.
+func isCleanEOF(err error) bool {
+	var decodeErr *DecodeError
+	return errors.As(err, &decodeErr) && decodeErr.Offset == 0 &&
+		errors.Is(decodeErr.Err, io.EOF)
+}
+
+// FromHexReader decodes a single hex encoded transaction read from r,
+// decoding the hex on the fly instead of holding the whole string in memory.
+// Like HexDecodeRawTxString it skips whitespace and a leading 0x prefix, except
+// that only ASCII whitespace is recognised. r is read one byte at a time and
+// never past the last hex character of the transaction, so whatever follows
+// it is left unread. Invalid hex characters wrap ErrInvalidHex.
+func FromHexReader(r io.Reader, net string) (txReply TxRawDecodeResult, err error) {
+	return FromReader(&hexReader{r: r}, net)
+}
+
+// hexReader decodes the hex characters of r, reading only as many of them as
+// the caller asks bytes for.
+type hexReader struct {
+	r       io.Reader
+	buf     [1]byte
+	started bool
+}
+
+func (h *hexReader) Read(p []byte) (int, error) {
+	for n := range p {
+		hi, err := h.next()
+		if err != nil {
+			return n, err
+		}
+		if !h.started {
+			h.started = true
+			if hi == '0' {
+				lo, err := h.next()
+				if err != nil {
+					return n, unexpectedEOF(err)
+				}
+				if lo != 'x' && lo != 'X' {
+					b, err := hexByte(hi, lo)
+					if err != nil {
+						return n, err
+					}
+					p[n] = b
+					continue
+				}
+				if hi, err = h.next(); err != nil {
+					return n, err
+				}
+			}
+		}
+		lo, err := h.next()
+		if err != nil {
+			return n, unexpectedEOF(err)
+		}
+		b, err := hexByte(hi, lo)
+		if err != nil {
+			return n, err
+		}
+		p[n] = b
+	}
+	return len(p), nil
+}
+
+// next returns the next non whitespace character of r.
+func (h *hexReader) next() (byte, error) {
+	for {
+		if _, err := io.ReadFull(h.r, h.buf[:]); err != nil {
+			return 0, err
+		}
+		switch c := h.buf[0]; c {
+		case ' ', '\t', '\n', '\v', '\f', '\r':
+		default:
+			return c, nil
+		}
+	}
+}
+
+// hexByte decodes the byte spelled by the hex characters hi and lo.
+func hexByte(hi, lo byte) (byte, error) {
+	var b [1]byte
+	if _, err := hex.Decode(b[:], []byte{hi, lo}); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidHex, err)
+	}
+	return b[0], nil
+}
+
+// unexpectedEOF reports an EOF falling between the two characters of a byte
+// as io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
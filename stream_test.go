@@ -0,0 +1,58 @@
+package rawdecodebtc
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestFromHexReader checks that FromHexReader accepts the formatting
+// HexDecodeRawTxString does and leaves the data after the transaction unread.
+func TestFromHexReader(t *testing.T) {
+	wrapped := strings.Join([]string{segwitTxHex[:64], segwitTxHex[64:130], segwitTxHex[130:]}, "\n\t")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "plain", input: segwitTxHex, want: segwitTxHex},
+		{name: "0x prefix", input: "0x" + legacyTxHex, want: legacyTxHex},
+		{name: "0X prefix and spaces", input: "  0X" + legacyTxHex, want: legacyTxHex},
+		{name: "wrapped", input: wrapped, want: segwitTxHex},
+		{name: "invalid hex", input: "0100zz", wantErr: ErrInvalidHex},
+		{name: "odd length", input: legacyTxHex[:len(legacyTxHex)-1], wantErr: io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txReply, err := FromHexReader(strings.NewReader(tt.input), "testnet")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromHexReader: %v", err)
+			}
+			got, err := txReply.ToHex()
+			if err != nil {
+				t.Fatalf("ToHex: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("decoded a different transaction")
+			}
+		})
+	}
+
+	r := strings.NewReader(legacyTxHex + "trailing")
+	if _, err := FromHexReader(r, "testnet"); err != nil {
+		t.Fatalf("FromHexReader: %v", err)
+	}
+	rest, _ := io.ReadAll(r)
+	if string(rest) != "trailing" {
+		t.Errorf("left %q unread, want %q", rest, "trailing")
+	}
+}
@@ -0,0 +1,98 @@
+package rawdecodebtc
+
+import "github.com/btcsuite/btcd/wire"
+
+const (
+	// taprootAnnexTag is the first byte of the optional annex, the last
+	// witness element of a taproot spend when more than one is present.
+	taprootAnnexTag = 0x50
+
+	// taprootLeafTapscript is the leaf version of BIP342 tapscripts.
+	taprootLeafTapscript = 0xc0
+
+	// taprootLeafMask extracts the leaf version from the first byte of a
+	// control block, the low bit being the output key parity.
+	taprootLeafMask = 0xfe
+
+	// Control blocks hold the leaf version byte and the 32-byte internal
+	// key followed by up to 128 32-byte merkle path nodes.
+	taprootControlBaseSize = 33
+	taprootControlNodeSize = 32
+	taprootControlMaxNodes = 128
+)
+
+// TaprootSpendInfo describes the witness of a P2TR (BIP341) input.
+type TaprootSpendInfo struct {
+	// KeyPath is true for key path spends, which only carry a signature,
+	// and false for script path spends.
+	KeyPath bool
+
+	// Signature is the Schnorr signature of a key path spend.
+	Signature []byte
+
+	// Annex holds the annex, including its 0x50 tag, when present.
+	Annex []byte
+
+	// The following fields are only set for script path spends.
+	LeafVersion  byte
+	LeafScript   []byte
+	ControlBlock []byte
+	InternalKey  []byte
+	ScriptInputs [][]byte
+}
+
+// ParseTaprootWitness interprets witness as the witness of a taproot spend.
+// The witness alone does not reveal the output being spent, so this is a
+// shape check: a key path spend is a single 64 or 65 byte signature, and a
+// script path spend ends with a well formed control block for a tapscript
+// leaf. The boolean is false when witness matches neither shape.
+func ParseTaprootWitness(witness wire.TxWitness) (*TaprootSpendInfo, bool) {
+	if len(witness) == 0 {
+		return nil, false
+	}
+
+	info := &TaprootSpendInfo{}
+	stack := witness
+	if len(stack) > 1 {
+		last := stack[len(stack)-1]
+		if len(last) > 0 && last[0] == taprootAnnexTag {
+			info.Annex = last
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(stack) == 1 {
+		sig := stack[0]
+		if len(sig) != 64 && len(sig) != 65 {
+			return nil, false
+		}
+		info.KeyPath = true
+		info.Signature = sig
+		return info, true
+	}
+
+	control := stack[len(stack)-1]
+	if !isControlBlock(control) {
+		return nil, false
+	}
+	info.ControlBlock = control
+	info.LeafVersion = control[0] & taprootLeafMask
+	info.InternalKey = control[1:taprootControlBaseSize]
+	info.LeafScript = stack[len(stack)-2]
+	info.ScriptInputs = stack[:len(stack)-2]
+	return info, true
+}
+
+// isControlBlock reports whether b has the size of a control block and
+// commits to a tapscript leaf.
+func isControlBlock(b []byte) bool {
+	if len(b) < taprootControlBaseSize {
+		return false
+	}
+	pathLen := len(b) - taprootControlBaseSize
+	if pathLen%taprootControlNodeSize != 0 ||
+		pathLen/taprootControlNodeSize > taprootControlMaxNodes {
+		return false
+	}
+	return b[0]&taprootLeafMask == taprootLeafTapscript
+}
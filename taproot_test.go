@@ -0,0 +1,115 @@
+package rawdecodebtc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestParseTaprootWitness checks ParseTaprootWitness on witnesses laid out as
+// in BIP341, with and without an annex, and on a P2WPKH witness.
+func TestParseTaprootWitness(t *testing.T) {
+	// The BIP341 NUMS point, the usual internal key of script only outputs.
+	nums, _ := hex.DecodeString("50929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0")
+	sig := bytes.Repeat([]byte{0x11}, 64)
+	sigWithType := append(bytes.Repeat([]byte{0x11}, 64), 0x83)
+	annex := []byte{taprootAnnexTag, 0x00, 0x01}
+	// <32-byte key> OP_CHECKSIG, a BIP342 single key tapscript.
+	leafScript := append(append([]byte{0x20}, bytes.Repeat([]byte{0x22}, 32)...), 0xac)
+	control := append([]byte{0xc0}, nums...)
+	controlWithPath := append(append([]byte{0xc1}, nums...),
+		bytes.Repeat([]byte{0x33}, 32)...)
+
+	tests := []struct {
+		name        string
+		witness     wire.TxWitness
+		wantOK      bool
+		wantKeyPath bool
+		wantAnnex   []byte
+		wantInputs  int
+		wantControl []byte
+	}{
+		{
+			name:        "key path",
+			witness:     wire.TxWitness{sig},
+			wantOK:      true,
+			wantKeyPath: true,
+		},
+		{
+			name:        "key path with annex",
+			witness:     wire.TxWitness{sigWithType, annex},
+			wantOK:      true,
+			wantKeyPath: true,
+			wantAnnex:   annex,
+		},
+		{
+			name:        "script path",
+			witness:     wire.TxWitness{sig, leafScript, control},
+			wantOK:      true,
+			wantInputs:  1,
+			wantControl: control,
+		},
+		{
+			name:        "script path with annex",
+			witness:     wire.TxWitness{sig, leafScript, controlWithPath, annex},
+			wantOK:      true,
+			wantAnnex:   annex,
+			wantInputs:  1,
+			wantControl: controlWithPath,
+		},
+		{
+			name: "P2WPKH",
+			witness: wire.TxWitness{
+				append(bytes.Repeat([]byte{0x30}, 70), 0x01),
+				append([]byte{0x02}, nums...),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		info, ok := ParseTaprootWitness(test.witness)
+		if ok != test.wantOK {
+			t.Fatalf("%s: ok %v, want %v", test.name, ok, test.wantOK)
+		}
+		if !ok {
+			continue
+		}
+		if info.KeyPath != test.wantKeyPath {
+			t.Errorf("%s: KeyPath %v, want %v", test.name, info.KeyPath,
+				test.wantKeyPath)
+		}
+		if !bytes.Equal(info.Annex, test.wantAnnex) {
+			t.Errorf("%s: annex %x, want %x", test.name, info.Annex,
+				test.wantAnnex)
+		}
+		if test.wantKeyPath {
+			if !bytes.Equal(info.Signature, test.witness[0]) {
+				t.Errorf("%s: signature %x, want %x", test.name,
+					info.Signature, test.witness[0])
+			}
+			continue
+		}
+		if info.LeafVersion != taprootLeafTapscript {
+			t.Errorf("%s: leaf version %#x, want %#x", test.name,
+				info.LeafVersion, taprootLeafTapscript)
+		}
+		if !bytes.Equal(info.InternalKey, nums) {
+			t.Errorf("%s: internal key %x, want %x", test.name,
+				info.InternalKey, nums)
+		}
+		if !bytes.Equal(info.LeafScript, leafScript) {
+			t.Errorf("%s: leaf script %x, want %x", test.name,
+				info.LeafScript, leafScript)
+		}
+		if !bytes.Equal(info.ControlBlock, test.wantControl) {
+			t.Errorf("%s: control block %x, want %x", test.name,
+				info.ControlBlock, test.wantControl)
+		}
+		if len(info.ScriptInputs) != test.wantInputs {
+			t.Errorf("%s: %d script inputs, want %d", test.name,
+				len(info.ScriptInputs), test.wantInputs)
+		}
+	}
+}
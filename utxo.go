@@ -0,0 +1,42 @@
+package rawdecodebtc
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// UTXOProvider looks up the outputs spent by a transaction, for instance
+// from a database or a node RPC, for decoding with WithUTXOProvider.
+type UTXOProvider interface {
+	// Lookup returns the value in satoshi and the pkScript of the output
+	// op, or an error when it cannot be found.
+	Lookup(op wire.OutPoint) (value int64, pkScript []byte, err error)
+}
+
+// annotateInputs looks up the output spent by every input of mtx with
+// provider, sets the value and script type of each Vin of txReply and then
+// its fee as setFee does. Coinbase transactions spend nothing and are left
+// untouched.
+func annotateInputs(txReply *TxRawDecodeResult, mtx *wire.MsgTx, provider UTXOProvider, unit btcutil.AmountUnit) error {
+	if blockchain.IsCoinBaseTx(mtx) {
+		return nil
+	}
+
+	prevouts := make(map[wire.OutPoint]int64, len(mtx.TxIn))
+	for i, txIn := range mtx.TxIn {
+		op := txIn.PreviousOutPoint
+		value, pkScript, err := provider.Lookup(op)
+		if err != nil {
+			return fmt.Errorf("lookup prevout for input %v: %w", op, err)
+		}
+
+		prevouts[op] = value
+		txReply.Vin[i].Value = btcutil.Amount(value).ToUnit(unit)
+		txReply.Vin[i].PrevScriptType = txscript.GetScriptClass(pkScript).String()
+	}
+	return setFee(txReply, mtx, prevouts, unit)
+}
@@ -0,0 +1,18 @@
+package rawdecodebtc
+
+// segwitTxHex is a testnet transaction spending a P2SH-P2WPKH output.
+const segwitTxHex = "0200000000010138ced909df7a622a2900582b5c835ccf117e8662456d168431ba4776163c596d01000000171600149bd5a504ea160c712c0d19bb7d9626843bd0b896feffffff0210abd4280100000017a91439d19119a39855212fe1ddadc19dd2d0ed4c608887809698000000000017a91488a28c267b1cc89accff9ca7464b06dc5ab7ed8d87024730440220127508b598ee90b3476a2cb44d4c6e456f4e81e0aaf4a41db006bf8bf254cc240220690ba56eccdd5180fbf4da8a8418195de43d4c542a2f046346850ade30d756af012102360aea2eb65297f282ef75b277c890608116ec56829a938e7a782eb88287bd2100000000"
+
+// legacyTxHex is a testnet transaction spending a P2PKH output.
+const legacyTxHex = "0100000001965f8b439470ae2157d8014bc39390b8899f7d643047b1619652cce1e85470ae000000006b483045022100bf4c8bff0dcb98ad6a9a2c28524078b19996bfa7c0bd099a5390152a43d9f83f0220369002b7b7f9a832fb43f945b83cd169b65a3e90882f6871374871323240b5f70121038fc506ca7d8e6f73510bf568a36871f54b2fb4c019e9b52a9bee8f5bacdb348bffffffff0103b43a00000000001976a914a7e32aaf8d24bf138be271ade0e135328f6e335a88ac00000000"
+
+// bip174PSBT is the BIP174 test vector of a PSBT with one P2PKH input and
+// its non-witness UTXO.
+const bip174PSBT = "cHNidP8BAHUCAAAAASaBcTce3/KF6Tet7qSze3gADAVmy7OtZGQXE8pCFxv2AAAAAAD+////AtPf9QUAAAAAGXapFNDFmQPFusKGh2DpD9UhpGZap2UgiKwA4fUFAAAAABepFDVF5uM7gyxHBQ8k0+65PJwDlIvHh7MuEwAAAQD9pQEBAAAAAAECiaPHHqtNIOA3G7ukzGmPopXJRjr6Ljl/hTPMti+VZ+UBAAAAFxYAFL4Y0VKpsBIDna89p95PUzSe7LmF/////4b4qkOnHf8USIk6UwpyN+9rRgi7st0tAXHmOuxqSJC0AQAAABcWABT+Pp7xp0XpdNkCxDVZQ6vLNL1TU/////8CAMLrCwAAAAAZdqkUhc/xCX/Z4Ai7NK9wnGIZeziXikiIrHL++E4sAAAAF6kUM5cluiHv1irHU6m80GfWx6ajnQWHAkcwRAIgJxK+IuAnDzlPVoMR3HyppolwuAJf3TskAinwf4pfOiQCIAGLONfc0xTnNMkna9b7QPZzMlvEuqFEyADS8vAtsnZcASED0uFWdJQbrUqZY3LLh+GFbTZSYG2YVi/jnF6efkE/IQUCSDBFAiEA0SuFLYXc2WHS9fSrZgZU327tzHlMDDPOXMMJ/7X85Y0CIGczio4OFyXBl/saiK9Z9R5E5CVbIBZ8hoQDHAXR8lkqASECI7cr7vCWXRC+B3jv7NYfysb3mk6haTkzgHNEZPhPKrMAAAAAAAAA"
+
+// genesisBlockHex is the mainnet genesis block.
+const genesisBlockHex = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c0101000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+
+// block170Hex is mainnet block 170, holding the first transaction spending
+// another one than a coinbase.
+const block170Hex = "0100000055bd840a78798ad0da853f68974f3d183e2bd1db6a842c1feecf222a00000000ff104ccb05421ab93e63f8c3ce5c2c2e9dbb37de2764b3a3175c8166562cac7d51b96a49ffff001d283e9e700201000000010000000000000000000000000000000000000000000000000000000000000000ffffffff0704ffff001d0102ffffffff0100f2052a01000000434104d46c4968bde02899d2aa0963367c7a6ce34eec332b32e42e5f3407e052d64ac625da6f0718e7b302140434bd725706957c092db53805b821a85b23a7ac61725bac000000000100000001c997a5e56e104102fa209c6a852dd90660a20b2d9c352423edce25857fcd3704000000004847304402204e45e16932b8af514961a1d3a1a25fdf3f4f7732e9d624c6c61548ab5fb8cd410220181522ec8eca07de4860a4acdd12909d831cc56cbbac4622082221a8768d1d0901ffffffff0200ca9a3b00000000434104ae1a62fe09c5f51b13905f07f06b99a2f7159b2225f374cd378d71302fa28414e7aab37397f554a7df5f142c21c1b7303b8a0626f1baded5c72a704f7e6cd84cac00286bee0000000043410411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0eaddfb84ccf9744464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac00000000"